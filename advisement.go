@@ -0,0 +1,284 @@
+package uwquest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	gq "github.com/PuerkitoBio/goquery"
+	ess "github.com/unixpickle/essentials"
+)
+
+// A DegreeAudit is an academic advisement report, which shows how a student's
+// courses apply towards the requirements of their program.
+type DegreeAudit struct {
+	Requirements []*Requirement
+}
+
+func (da *DegreeAudit) String() string {
+	return fmt.Sprintf("DegreeAudit{Requirements: %v}", da.Requirements)
+}
+
+// Walk calls fn for every requirement in da, parents before their children.
+// Top-level requirements have a depth of 0.
+func (da *DegreeAudit) Walk(fn func(r *Requirement, depth int)) {
+	for _, r := range da.Requirements {
+		r.walk(fn, 0)
+	}
+}
+
+// Satisfied reports whether every top-level requirement is satisfied.
+func (da *DegreeAudit) Satisfied() bool {
+	for _, r := range da.Requirements {
+		if !r.Satisfied {
+			return false
+		}
+	}
+	return true
+}
+
+// Checklist formats da as an indented checklist.
+func (da *DegreeAudit) Checklist() string {
+	var buf bytes.Buffer
+	da.WriteChecklist(&buf)
+	return buf.String()
+}
+
+// WriteChecklist writes da to w as an indented checklist, in which each
+// requirement is followed by the courses that apply to it.
+func (da *DegreeAudit) WriteChecklist(w io.Writer) error {
+	var err error
+	da.Walk(func(r *Requirement, depth int) {
+		if err != nil {
+			return
+		}
+		indent := strings.Repeat("  ", depth)
+		mark := " "
+		if r.Satisfied {
+			mark = "x"
+		}
+		line := fmt.Sprintf("%s[%s] %s", indent, mark, r.Title)
+		if r.UnitsRequired != nil && r.UnitsUsed != nil {
+			line += fmt.Sprintf(" (%.2f/%.2f units)", *r.UnitsUsed,
+				*r.UnitsRequired)
+		}
+		if _, err = fmt.Fprintln(w, line); err != nil {
+			return
+		}
+		for _, course := range r.Courses {
+			if _, err = fmt.Fprintf(w, "%s    - %s\n", indent,
+				course.summary()); err != nil {
+				return
+			}
+		}
+	})
+	return err
+}
+
+// A Requirement is a requirement (or group of requirements) in a DegreeAudit.
+type Requirement struct {
+	Title       string
+	Description string
+	Satisfied   bool
+
+	// UnitsRequired and UnitsUsed are nil if the requirement is not measured in
+	// units.
+	UnitsRequired *float32
+	UnitsUsed     *float32
+
+	Courses  []*AppliedCourse // the courses that apply to the requirement
+	Children []*Requirement
+}
+
+func (r *Requirement) String() string {
+	return fmt.Sprintf("Requirement{Title: %s, Description: %s, Satisfied: %t, "+
+		"UnitsRequired: %s, UnitsUsed: %s, Courses: %v, Children: %v}", r.Title,
+		r.Description, r.Satisfied, formatOptionalFloat(r.UnitsRequired),
+		formatOptionalFloat(r.UnitsUsed), r.Courses, r.Children)
+}
+
+func (r *Requirement) walk(fn func(r *Requirement, depth int), depth int) {
+	fn(r, depth)
+	for _, child := range r.Children {
+		child.walk(fn, depth+1)
+	}
+}
+
+// An AppliedCourse is a course that applies towards a Requirement.
+type AppliedCourse struct {
+	Course string // i.e. "CS 135"
+	Title  string
+	Term   string
+	Grade  string
+	Units  *float32
+}
+
+func (ac *AppliedCourse) String() string {
+	return fmt.Sprintf("AppliedCourse{Course: %s, Title: %s, Term: %s, "+
+		"Grade: %s, Units: %s}", ac.Course, ac.Title, ac.Term, ac.Grade,
+		formatOptionalFloat(ac.Units))
+}
+
+// summary formats ac for a checklist, i.e. "CS 135 (Fall 2017, 85)".
+func (ac *AppliedCourse) summary() string {
+	var details []string
+	for _, s := range []string{ac.Term, ac.Grade} {
+		if s != "" {
+			details = append(details, s)
+		}
+	}
+	if len(details) == 0 {
+		return ac.Course
+	}
+	return fmt.Sprintf("%s (%s)", ac.Course, strings.Join(details, ", "))
+}
+
+func formatOptionalFloat(f *float32) string {
+	if f == nil {
+		return "<nil>"
+	}
+	return strconv.FormatFloat(float64(*f), 'f', -1, 32)
+}
+
+// DegreeAudit requests the student's academic advisement report, and parses
+// its requirements.
+func (c *Client) DegreeAudit() (*DegreeAudit, error) {
+	page, err := c.fetchPage(AdvisementURL)
+	if err != nil {
+		return nil, ess.AddCtx("uwquest: fetching advisement report", err)
+	}
+
+	// Requirements that Quest has collapsed are not included in the page, so
+	// expand them all.
+	const expandAction = "DERIVED_SAA_DPR_SSS_EXPAND_ALL"
+	if page.Find("#"+expandAction).Length() > 0 {
+		if page, err = c.submitPage(AdvisementURL, page, expandAction,
+			nil); err != nil {
+			return nil, ess.AddCtx("uwquest: expanding advisement report", err)
+		}
+	}
+	if msg := pageError(page.Selection); msg != "" {
+		return nil, fmt.Errorf("uwquest: fetching advisement report: %s", msg)
+	}
+
+	audit := new(DegreeAudit)
+	audit.Requirements, err = parseRequirements(page.Selection, 1)
+	return audit, ess.AddCtx("uwquest: parsing advisement report", err)
+}
+
+// maxRequirementDepth is the depth of the most deeply nested requirement boxes
+// in an advisement report.
+const maxRequirementDepth = 3
+
+// requirementBoxSelector selects the requirement boxes at a particular depth
+// (from 1 to maxRequirementDepth).
+func requirementBoxSelector(depth int) string {
+	return fmt.Sprintf(`[id^="win0divDERIVED_SAA_DPR_GROUPBOX%d$"]`, depth)
+}
+
+// unitsRegexp matches the units summary of a requirement, i.e.
+// "Units: 20.00 required, 15.50 used, 4.50 needed".
+var unitsRegexp = regexp.MustCompile(
+	`Units:\s*([\d.]+)\s+required,\s*([\d.]+)\s+(?:used|taken)`)
+
+// parseRequirements parses the requirement boxes at a particular depth within
+// sel, along with their children.
+func parseRequirements(sel *gq.Selection, depth int) ([]*Requirement, error) {
+	var (
+		reqs []*Requirement
+		err  error
+	)
+	sel.Find(requirementBoxSelector(depth)).EachWithBreak(
+		func(i int, box *gq.Selection) bool {
+			var req *Requirement
+			if req, err = parseRequirement(box, depth); err != nil {
+				ess.AddCtxTo(fmt.Sprintf("requirement %d (depth %d)", i, depth),
+					&err)
+				return false
+			}
+			reqs = append(reqs, req)
+			return true
+		})
+	return reqs, err
+}
+
+// parseRequirementStatus returns the status of a requirement (i.e.
+// "Satisfied", "Not Satisfied", or "In Progress") from its status icon's alt
+// text, or from the status cell's text if it has no icon.
+func parseRequirementStatus(sel *gq.Selection) string {
+	if alt, ok := sel.Find("img").Attr("alt"); ok {
+		return strings.TrimSpace(alt)
+	}
+	if alt, ok := sel.Attr("alt"); ok {
+		return strings.TrimSpace(alt)
+	}
+	return strings.TrimSpace(sel.Text())
+}
+
+func parseRequirement(box *gq.Selection, depth int) (*Requirement, error) {
+	// Only look at the parts of the box that don't belong to its children.
+	own := box.Clone()
+	if depth < maxRequirementDepth {
+		own.Find(requirementBoxSelector(depth + 1)).Remove()
+	}
+
+	req := new(Requirement)
+	header := own.Find(fmt.Sprintf(
+		`[id^="win0divDERIVED_SAA_DPR_GROUPBOX%dGP$"]`, depth)).First()
+	req.Title = strings.Join(textLines(header), " ")
+	req.Description = strings.Join(textLines(own.Find(
+		`[id^="DERIVED_SAA_DPR_SSR_DESCRLONG"]`).First()), " ")
+
+	req.Satisfied = parseRequirementStatus(own.Find(
+		`[id^="DERIVED_SAA_DPR_STATUS_IMG"]`).First()) == "Satisfied"
+
+	text := own.Text()
+	if match := unitsRegexp.FindStringSubmatch(text); match != nil {
+		var err error
+		if req.UnitsRequired, err = parseOptionalFloat(match[1]); err != nil {
+			return nil, ess.AddCtx("parsing units required", err)
+		}
+		if req.UnitsUsed, err = parseOptionalFloat(match[2]); err != nil {
+			return nil, ess.AddCtx("parsing units used", err)
+		}
+	}
+
+	var err error
+	own.Find(`[id^="CRSE_NAME$"]`).EachWithBreak(
+		func(i int, name *gq.Selection) bool {
+			var index int
+			if index, err = selectionIndex(name); err != nil {
+				return false
+			}
+			id := func(field string) string {
+				return fmt.Sprintf("%s$%d", field, index)
+			}
+			course := &AppliedCourse{
+				Course: strings.TrimSpace(name.Text()),
+				Title:  fieldText(own, id("CRSE_DESCR")),
+				Term:   fieldText(own, id("CRSE_TERM")),
+				Grade:  fieldText(own, id("CRSE_GRADE")),
+			}
+			course.Units, err = parseOptionalFloat(fieldText(own,
+				id("CRSE_UNITS")))
+			if err != nil {
+				ess.AddCtxTo(fmt.Sprintf("course %d: parsing units", i), &err)
+				return false
+			}
+			req.Courses = append(req.Courses, course)
+			return true
+		})
+	if err != nil {
+		return nil, err
+	}
+
+	if depth < maxRequirementDepth {
+		if req.Children, err = parseRequirements(box, depth+1); err != nil {
+			return nil, err
+		}
+	}
+	return req, nil
+}
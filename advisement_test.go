@@ -0,0 +1,50 @@
+package uwquest_test
+
+import (
+	"testing"
+
+	"github.com/stevenxie/uwquest"
+)
+
+func TestClient_DegreeAudit(t *testing.T) {
+	audit, err := client.DegreeAudit()
+	if err != nil {
+		t.Fatalf("Error while fetching degree audit: %v", err)
+	}
+
+	t.Logf("Got degree audit:\n%s", audit.Checklist())
+}
+
+func TestDegreeAudit_Checklist(t *testing.T) {
+	var (
+		required, used = float32(2), float32(0.5)
+		audit          = &uwquest.DegreeAudit{
+			Requirements: []*uwquest.Requirement{{
+				Title: "Math Requirements",
+				Children: []*uwquest.Requirement{
+					{
+						Title:         "Core",
+						Satisfied:     true,
+						UnitsRequired: &required,
+						UnitsUsed:     &used,
+						Courses: []*uwquest.AppliedCourse{
+							{Course: "MATH 135", Term: "Fall 2017", Grade: "90"},
+						},
+					},
+					{Title: "Electives"},
+				},
+			}},
+		}
+	)
+
+	const want = "[ ] Math Requirements\n" +
+		"  [x] Core (0.50/2.00 units)\n" +
+		"      - MATH 135 (Fall 2017, 90)\n" +
+		"  [ ] Electives\n"
+	if got := audit.Checklist(); got != want {
+		t.Errorf("Expected checklist:\n%s\nGot:\n%s", want, got)
+	}
+	if audit.Satisfied() {
+		t.Error("Expected audit to be unsatisfied.")
+	}
+}
@@ -0,0 +1,149 @@
+package uwquest
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	gq "github.com/PuerkitoBio/goquery"
+	ess "github.com/unixpickle/essentials"
+)
+
+// An Appointment is an enrollment appointment: a window of time during which a
+// student may enroll in classes for a term.
+type Appointment struct {
+	Index   int
+	Session string
+	Start   time.Time // in Location
+	End     time.Time // in Location
+
+	// The maximum number of units that may be enrolled in during the
+	// appointment; these may be nil if Quest leaves them blank.
+	MaxUnits      *float32
+	MaxNoGPAUnits *float32
+	MaxAuditUnits *float32
+}
+
+func (a *Appointment) String() string {
+	sb := new(strings.Builder)
+	fmt.Fprintf(sb, "Appointment{Index: %d, Session: %s, Start: %s, End: %s",
+		a.Index, a.Session, a.Start.Format(time.RFC3339),
+		a.End.Format(time.RFC3339))
+	for _, field := range []struct {
+		Name  string
+		Value *float32
+	}{
+		{"MaxUnits", a.MaxUnits},
+		{"MaxNoGPAUnits", a.MaxNoGPAUnits},
+		{"MaxAuditUnits", a.MaxAuditUnits},
+	} {
+		if field.Value == nil {
+			fmt.Fprintf(sb, ", %s: <nil>", field.Name)
+		} else {
+			fmt.Fprintf(sb, ", %s: %f", field.Name, *field.Value)
+		}
+	}
+	sb.WriteByte('}')
+	return sb.String()
+}
+
+// OpenAt reports whether the appointment is open at time t.
+func (a *Appointment) OpenAt(t time.Time) bool {
+	return !t.Before(a.Start) && t.Before(a.End)
+}
+
+// EnrollmentOpen reports whether any of appts is open right now.
+func EnrollmentOpen(appts []*Appointment) bool {
+	now := time.Now()
+	for _, appt := range appts {
+		if appt.OpenAt(now) {
+			return true
+		}
+	}
+	return false
+}
+
+// EnrollmentAppointments fetches the enrollment appointments for a particular
+// term.
+func (c *Client) EnrollmentAppointments(termIndex int) ([]*Appointment,
+	error) {
+	page, err := c.selectTerm(EnrollmentDatesURL, termIndex)
+	if err != nil {
+		return nil, ess.AddCtx("uwquest: fetching enrollment dates page", err)
+	}
+
+	sel := page.Find(`#SSR_APPTSTDNT\$scroll\$0`)
+	if sel.Length() != 1 {
+		return nil, errors.New("uwquest: could not locate enrollment " +
+			"appointments table")
+	}
+
+	var appts []*Appointment
+	sel.Find(`[id^="SESSION_TBL_DESCR$"]`).EachWithBreak(
+		func(i int, session *gq.Selection) bool {
+			var index int
+			if index, err = selectionIndex(session); err != nil {
+				return false
+			}
+
+			var appt *Appointment
+			if appt, err = parseAppointmentRow(sel, index); err != nil {
+				ess.AddCtxTo(fmt.Sprintf("row %d", i), &err)
+				return false
+			}
+			appts = append(appts, appt)
+			return true
+		})
+	return appts, ess.AddCtx("uwquest: parsing enrollment appointments", err)
+}
+
+func parseAppointmentRow(table *gq.Selection, index int) (*Appointment,
+	error) {
+	var (
+		appt     = &Appointment{Index: index}
+		scraper  = indexedScraper{Index: index, Sel: table}
+		sel, err = scraper.Find("SESSION_TBL_DESCR", "session")
+	)
+	if err != nil {
+		return nil, err
+	}
+	appt.Session = strings.TrimSpace(sel.Text())
+
+	var text [4]string
+	for i, field := range []struct{ ID, Desc string }{
+		{"SSR_APPTSTDNT_APPT_START_DT", "start date"},
+		{"SSR_APPTSTDNT_APPT_START_TM", "start time"},
+		{"SSR_APPTSTDNT_APPT_END_DT", "end date"},
+		{"SSR_APPTSTDNT_APPT_END_TM", "end time"},
+	} {
+		if sel, err = scraper.Find(field.ID, field.Desc); err != nil {
+			return nil, err
+		}
+		text[i] = sel.Text()
+	}
+	if appt.Start, err = parseQuestTime(text[0], text[1]); err != nil {
+		return nil, ess.AddCtx("parsing start time", err)
+	}
+	if appt.End, err = parseQuestTime(text[2], text[3]); err != nil {
+		return nil, ess.AddCtx("parsing end time", err)
+	}
+
+	for _, field := range []struct {
+		ID, Desc string
+		Dest     **float32
+	}{
+		{"SSR_APPT_TBL_MAX_TOTAL_UNIT", "max total units", &appt.MaxUnits},
+		{"SSR_APPT_TBL_MAX_NOGPA_UNIT", "max no GPA units", &appt.MaxNoGPAUnits},
+		{"SSR_APPT_TBL_MAX_AUDIT_UNIT", "max audit units", &appt.MaxAuditUnits},
+	} {
+		if sel, err = scraper.Find(field.ID, field.Desc); err != nil {
+			return nil, err
+		}
+		if *field.Dest, err = parseOptionalFloat(sel.Text()); err != nil {
+			return nil, ess.AddCtx(fmt.Sprintf("parsing %s string into float",
+				field.Desc), err)
+		}
+	}
+	return appt, nil
+}
@@ -0,0 +1,32 @@
+package uwquest_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stevenxie/uwquest"
+)
+
+func TestClient_EnrollmentAppointments(t *testing.T) {
+	appts, err := client.EnrollmentAppointments(0)
+	if err != nil {
+		t.Fatalf("Error while fetching enrollment appointments: %v", err)
+	}
+
+	t.Logf("Got enrollment appointments for term 0: %v", appts)
+}
+
+func TestAppointment_OpenAt(t *testing.T) {
+	start := time.Date(2018, 7, 16, 8, 0, 0, 0, uwquest.Location)
+	appt := &uwquest.Appointment{Start: start, End: start.Add(24 * time.Hour)}
+
+	if !appt.OpenAt(start) {
+		t.Error("Expected appointment to be open at its start time.")
+	}
+	if appt.OpenAt(start.Add(-time.Minute)) {
+		t.Error("Expected appointment to be closed before its start time.")
+	}
+	if appt.OpenAt(appt.End) {
+		t.Error("Expected appointment to be closed at its end time.")
+	}
+}
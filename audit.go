@@ -0,0 +1,71 @@
+package uwquest
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// An AuditEntry records a single request that changed data on Quest.
+type AuditEntry struct {
+	Time    time.Time
+	Action  string // i.e. "AddToCart"
+	Details string
+	Err     error // the error that the request failed with, if any
+}
+
+func (ae *AuditEntry) String() string {
+	return fmt.Sprintf("AuditEntry{Time: %s, Action: %s, Details: %s, Err: %v}",
+		ae.Time.Format(time.RFC3339), ae.Action, ae.Details, ae.Err)
+}
+
+// DefaultMaxAuditEntries is the number of entries that an AuditLog keeps,
+// unless it is configured otherwise.
+const DefaultMaxAuditEntries = 1000
+
+// An AuditLog records the requests that a Client makes which change data on
+// Quest (i.e. enrolling in a class). It is safe for concurrent use.
+type AuditLog struct {
+	// MaxEntries is the number of entries that the log keeps, after which the
+	// oldest entries are dropped. It defaults to DefaultMaxAuditEntries.
+	MaxEntries int
+
+	mu      sync.Mutex
+	entries []*AuditEntry
+}
+
+// Entries returns the entries in the log, from oldest to newest.
+func (l *AuditLog) Entries() []*AuditEntry {
+	if l == nil {
+		return nil
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entries := make([]*AuditEntry, len(l.entries))
+	copy(entries, l.entries)
+	return entries
+}
+
+// record adds an entry to the log. It does nothing if l is nil.
+func (l *AuditLog) record(action, details string, err error) {
+	if l == nil {
+		return
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	limit := l.MaxEntries
+	if limit <= 0 {
+		limit = DefaultMaxAuditEntries
+	}
+	if n := len(l.entries) - limit + 1; n > 0 {
+		l.entries = append(l.entries[:0], l.entries[n:]...)
+	}
+	l.entries = append(l.entries, &AuditEntry{
+		Time:    time.Now(),
+		Action:  action,
+		Details: details,
+		Err:     err,
+	})
+}
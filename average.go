@@ -0,0 +1,71 @@
+package uwquest
+
+import (
+	"fmt"
+
+	ess "github.com/unixpickle/essentials"
+)
+
+// An Average is a unit-weighted average of course grades.
+type Average struct {
+	// Value is the average itself; it is nil if no units count towards the
+	// average.
+	Value *float32
+
+	// Units is the number of units that count towards the average.
+	Units float32
+
+	// GradePoints is the sum of each counted grade's value multiplied by its
+	// units.
+	GradePoints float32
+}
+
+func (a *Average) String() string {
+	value := "<nil>"
+	if a.Value != nil {
+		value = fmt.Sprintf("%f", *a.Value)
+	}
+	return fmt.Sprintf("Average{Value: %s, Units: %f, GradePoints: %f}", value,
+		a.Units, a.GradePoints)
+}
+
+// TermAverage calculates the unit-weighted average of a term's course grades.
+//
+// Following UW rules, only grades that count towards averages (numeric
+// grades, as well as DNW and WF, which count as 32) are included; courses
+// without units are ignored.
+func TermAverage(grades []*CourseGrade) (*Average, error) {
+	return CumulativeAverage(grades)
+}
+
+// CumulativeAverage calculates the unit-weighted average of the course grades
+// across several terms.
+//
+// Repeated courses are counted once per attempt, as they are on Quest.
+func CumulativeAverage(terms ...[]*CourseGrade) (*Average, error) {
+	var points, units float64
+	for _, grades := range terms {
+		for _, cg := range grades {
+			if cg.Units == nil {
+				continue
+			}
+			grade, err := cg.ParsedGrade()
+			if err != nil {
+				return nil, ess.AddCtx(fmt.Sprintf("uwquest: parsing grade for %s",
+					cg.Name), err)
+			}
+			if !grade.InAverage {
+				continue
+			}
+			points += float64(grade.Value) * float64(*cg.Units)
+			units += float64(*cg.Units)
+		}
+	}
+
+	avg := &Average{Units: float32(units), GradePoints: float32(points)}
+	if units > 0 {
+		value := float32(points / units)
+		avg.Value = &value
+	}
+	return avg, nil
+}
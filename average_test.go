@@ -0,0 +1,102 @@
+package uwquest_test
+
+import (
+	"testing"
+
+	"github.com/stevenxie/uwquest"
+)
+
+func TestParseGrade(t *testing.T) {
+	cases := []struct {
+		Grade     string
+		Kind      uwquest.GradeKind
+		InAverage bool
+		Passing   bool
+	}{
+		{"71", uwquest.NumericGrade, true, true},
+		{"42", uwquest.NumericGrade, true, false},
+		{"", uwquest.InProgressGrade, false, false},
+		{"CR", uwquest.CreditGrade, false, true},
+		{"DNW", uwquest.DidNotWriteGrade, true, false},
+		{"WD", uwquest.WithdrawnGrade, false, false},
+		{"XYZ", uwquest.OtherGrade, false, false},
+	}
+	for _, c := range cases {
+		grade, err := uwquest.ParseGrade(c.Grade)
+		if err != nil {
+			t.Errorf("Error parsing grade '%s': %v", c.Grade, err)
+			continue
+		}
+		if grade.Kind != c.Kind || grade.InAverage != c.InAverage ||
+			grade.Passing != c.Passing {
+			t.Errorf("Parsed grade '%s' incorrectly: got %v", c.Grade, grade)
+		}
+	}
+
+	for _, bad := range []string{"7X", "101", "NaN", "Inf", "-Inf"} {
+		if _, err := uwquest.ParseGrade(bad); err == nil {
+			t.Errorf("Expected an error when parsing '%s'.", bad)
+		}
+	}
+}
+
+func TestTermAverage(t *testing.T) {
+	half := float32(0.5)
+	grades := []*uwquest.CourseGrade{
+		{Name: "CS 245", Units: &half, Grade: "DNW"},
+		{Name: "CS 246", Units: &half, Grade: "71"},
+		{Name: "MATH 128", Units: &half, Grade: "CR"},
+		{Name: "MATH 136", Units: &half, Grade: ""},
+		{Name: "PD 1", Units: &half, Grade: "NG"}, // not a known grade
+	}
+
+	avg, err := uwquest.TermAverage(grades)
+	if err != nil {
+		t.Fatalf("Error calculating term average: %v", err)
+	}
+	if avg.Value == nil || *avg.Value != 51.5 || avg.Units != 1 {
+		t.Errorf("Calculated term average incorrectly: got %v", avg)
+	}
+}
+
+func TestProject(t *testing.T) {
+	half := float32(0.5)
+	grades := []*uwquest.CourseGrade{
+		{Name: "CS 245", Units: &half, Grade: "DNW"},
+		{Name: "CS 246", Units: &half, Grade: "71"},
+		{Name: "MATH 135", Units: &half, Grade: ""},
+		{Name: "MATH 136", Units: &half, Grade: "IP"},
+	}
+
+	p, err := uwquest.Project(grades, &uwquest.WhatIf{
+		Hypothetical: map[string]float32{"MATH 135": 80},
+		Thresholds:   []uwquest.Threshold{{Name: "Target", Average: 70}},
+	})
+	if err != nil {
+		t.Fatalf("Error projecting averages: %v", err)
+	}
+	if len(p.Outstanding) != 1 || p.Outstanding[0] != "MATH 136" {
+		t.Errorf("Found wrong outstanding courses: got %v", p.Outstanding)
+	}
+
+	tr := p.Thresholds[0]
+	if tr.Met || !tr.Attainable || tr.Required["MATH 136"] != 97 {
+		t.Errorf("Projected threshold incorrectly: got %v", tr)
+	}
+}
+
+func TestProject_UnknownHypothetical(t *testing.T) {
+	half := float32(0.5)
+	grades := []*uwquest.CourseGrade{
+		{Name: "CS 246", Units: &half, Grade: "71"},
+		{Name: "MATH 136", Units: &half, Grade: "IP"},
+	}
+
+	for _, name := range []string{"MATH 163", "CS 246"} {
+		if _, err := uwquest.Project(grades, &uwquest.WhatIf{
+			Hypothetical: map[string]float32{name: 80},
+		}); err == nil {
+			t.Errorf("Expected an error for a hypothetical grade in %s", name)
+		}
+	}
+}
@@ -0,0 +1,385 @@
+package uwquest
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	gq "github.com/PuerkitoBio/goquery"
+	ess "github.com/unixpickle/essentials"
+)
+
+// A CartEntry represents a class in the shopping cart for a term.
+type CartEntry struct {
+	Index  int
+	Name   string // i.e. "CS 135-001"
+	Number int
+
+	// Related are the class numbers of the related components (i.e. a
+	// tutorial or lab) that were chosen for the class.
+	Related []int
+
+	Schedule   string
+	Location   string
+	Instructor string
+	Units      *float32 // may be nil
+	Status     ClassStatus
+
+	// Validation is the message from Quest's last validation of the entry;
+	// it is blank if the entry has not been validated.
+	Validation string
+}
+
+func (ce *CartEntry) String() string {
+	units := "<nil>"
+	if ce.Units != nil {
+		units = fmt.Sprintf("%f", *ce.Units)
+	}
+	return fmt.Sprintf("CartEntry{Index: %d, Name: %s, Number: %d, "+
+		"Related: %v, Schedule: %s, Location: %s, Instructor: %s, Units: %s, "+
+		"Status: %s, Validation: %s}", ce.Index, ce.Name, ce.Number, ce.Related,
+		ce.Schedule, ce.Location, ce.Instructor, units, ce.Status, ce.Validation)
+}
+
+// CartOptions are the enrollment preferences used when adding a class to the
+// shopping cart.
+type CartOptions struct {
+	// RelatedClasses are the class numbers of the related components to
+	// choose for the class, if it has any (i.e. a tutorial for a lecture).
+	RelatedClasses []int
+
+	// PermissionNumber is the permission number to enroll with; it is unused if
+	// zero.
+	PermissionNumber int
+
+	// Waitlist indicates whether to join the waitlist if the class is full.
+	Waitlist bool
+}
+
+// ErrNotInCart is returned when a class is not in the shopping cart.
+var ErrNotInCart = errors.New("uwquest: class is not in the shopping cart")
+
+// A RelatedComponentError is returned by AddToCart when a class has a related
+// component that was not chosen in CartOptions.RelatedClasses.
+type RelatedComponentError struct {
+	// Choices are the class numbers of the related component's classes.
+	Choices []int
+}
+
+func (err *RelatedComponentError) Error() string {
+	return fmt.Sprintf("uwquest: a related component must be chosen from "+
+		"classes %v", err.Choices)
+}
+
+// ShoppingCart fetches the shopping cart for a particular term.
+func (c *Client) ShoppingCart(termIndex int) ([]*CartEntry, error) {
+	page, err := c.selectTerm(ShoppingCartURL, termIndex)
+	if err != nil {
+		return nil, ess.AddCtx("uwquest: fetching shopping cart page", err)
+	}
+	entries, err := parseCart(page.Selection)
+	return entries, ess.AddCtx("uwquest: parsing shopping cart", err)
+}
+
+// AddToCart adds a class to the shopping cart for a particular term.
+//
+// If the class has related components, the classes to choose for each of them
+// must be given in opts.RelatedClasses; otherwise, a *RelatedComponentError is
+// returned. opts may be nil.
+func (c *Client) AddToCart(termIndex, classNumber int,
+	opts *CartOptions) (err error) {
+	defer func() {
+		c.Audit.record("AddToCart", fmt.Sprintf("term %d, class %d", termIndex,
+			classNumber), err)
+	}()
+
+	page, err := c.selectTerm(ShoppingCartURL, termIndex)
+	if err != nil {
+		return ess.AddCtx("uwquest: fetching shopping cart page", err)
+	}
+	form := make(url.Values)
+	form.Set("DERIVED_REGFRM1_CLASS_NBR", strconv.Itoa(classNumber))
+	page, err = c.addClass(ShoppingCartURL, page,
+		"DERIVED_REGFRM1_SSR_PB_ADDTOLIST2$9$", form, opts)
+	if err != nil {
+		return err
+	}
+
+	// Ensure that the class made it into the cart.
+	entries, err := parseCart(page.Selection)
+	if err != nil {
+		return ess.AddCtx("uwquest: parsing shopping cart", err)
+	}
+	if findCartEntry(entries, classNumber) == nil {
+		return fmt.Errorf("uwquest: class %d was not added to the shopping cart",
+			classNumber)
+	}
+	return nil
+}
+
+// RemoveFromCart removes a class from the shopping cart for a particular
+// term.
+//
+// It returns ErrNotInCart if the class is not in the shopping cart, in which
+// case nothing is recorded in c.Audit.
+func (c *Client) RemoveFromCart(termIndex, classNumber int) (err error) {
+	page, err := c.selectTerm(ShoppingCartURL, termIndex)
+	if err != nil {
+		return ess.AddCtx("uwquest: fetching shopping cart page", err)
+	}
+	entries, err := parseCart(page.Selection)
+	if err != nil {
+		return ess.AddCtx("uwquest: parsing shopping cart", err)
+	}
+	entry := findCartEntry(entries, classNumber)
+	if entry == nil {
+		return ErrNotInCart
+	}
+
+	// Only record the removal once it is requested.
+	defer func() {
+		c.Audit.record("RemoveFromCart", fmt.Sprintf("term %d, class %d",
+			termIndex, classNumber), err)
+	}()
+	action := fmt.Sprintf("P_DELETE$%d", entry.Index)
+	if page, err = c.submitPage(ShoppingCartURL, page, action,
+		nil); err != nil {
+		return ess.AddCtx("uwquest: removing class from shopping cart", err)
+	}
+	if msg := pageError(page.Selection); msg != "" {
+		return fmt.Errorf("uwquest: removing class from shopping cart: %s", msg)
+	}
+	return nil
+}
+
+// addClass submits an enrollment page (i.e. the shopping cart) using action
+// and fields that enter a class number, and steps through PeopleSoft's
+// related component and enrollment preference pages.
+//
+// It returns the page that PeopleSoft returns to afterwards.
+func (c *Client) addClass(pageURL string, page *gq.Document, action string,
+	fields url.Values, opts *CartOptions) (*gq.Document, error) {
+	if opts == nil {
+		opts = new(CartOptions)
+	}
+
+	page, err := c.submitPage(pageURL, page, action, fields)
+	if err != nil {
+		return nil, ess.AddCtx("uwquest: entering class number", err)
+	}
+	if msg := pageError(page.Selection); msg != "" {
+		return nil, fmt.Errorf("uwquest: entering class number: %s", msg)
+	}
+
+	// Choose related components, if the class has any.
+	if page.Find(`#SSR_CLS_TBL_R1\$scroll\$0`).Length() > 0 {
+		form, err := relatedComponentsForm(page.Selection, opts.RelatedClasses)
+		if err != nil {
+			return nil, err
+		}
+		if page, err = c.submitPage(pageURL, page, "DERIVED_CLS_DTL_NEXT_PB",
+			form); err != nil {
+			return nil, ess.AddCtx("uwquest: choosing related components", err)
+		}
+		if msg := pageError(page.Selection); msg != "" {
+			return nil, fmt.Errorf("uwquest: choosing related components: %s", msg)
+		}
+	}
+
+	return c.setPreferences(pageURL, page, opts.Waitlist, opts.PermissionNumber)
+}
+
+// setPreferences submits PeopleSoft's enrollment preferences page, choosing
+// whether to join the waitlist and the permission number to enroll with (if it
+// is not zero).
+//
+// It returns the page that PeopleSoft returns to afterwards.
+func (c *Client) setPreferences(pageURL string, page *gq.Document,
+	waitlist bool, permissionNumber int) (*gq.Document, error) {
+	form := make(url.Values)
+	if waitlist {
+		form.Set("DERIVED_CLS_DTL_WAIT_LIST_OKAY$125$$chk", "Y")
+		form.Set("DERIVED_CLS_DTL_WAIT_LIST_OKAY$125$", "Y")
+	} else {
+		form.Set("DERIVED_CLS_DTL_WAIT_LIST_OKAY$125$$chk", "N")
+	}
+	if permissionNumber != 0 {
+		form.Set("DERIVED_CLS_DTL_CLASS_PRMSN_NBR$118$",
+			strconv.Itoa(permissionNumber))
+	}
+	page, err := c.submitPage(pageURL, page, "DERIVED_CLS_DTL_NEXT_PB$280$",
+		form)
+	if err != nil {
+		return nil, ess.AddCtx("uwquest: setting enrollment preferences", err)
+	}
+	if msg := pageError(page.Selection); msg != "" {
+		return nil, fmt.Errorf("uwquest: setting enrollment preferences: %s", msg)
+	}
+	return page, nil
+}
+
+// relatedComponentsForm makes the form fields that choose each of the related
+// components on PeopleSoft's related components page from among related.
+func relatedComponentsForm(sel *gq.Selection, related []int) (url.Values,
+	error) {
+	chosen := make(map[int]bool, len(related))
+	for _, number := range related {
+		chosen[number] = true
+	}
+
+	form := make(url.Values)
+	for component := 1; ; component++ {
+		grid := fmt.Sprintf("SSR_CLS_TBL_R%d", component)
+		if sel.Find(fmt.Sprintf(`#%s\$scroll\$0`, grid)).Length() == 0 {
+			break
+		}
+
+		var (
+			cells   = sel.Find(fmt.Sprintf(`[id^="%s_RELATE_CLASS_NBR$"]`, grid))
+			choices []int
+			choice  = -1
+			err     error
+		)
+		cells.EachWithBreak(func(_ int, cell *gq.Selection) bool {
+			var index, number int
+			if index, err = selectionIndex(cell); err != nil {
+				return false
+			}
+			text := strings.TrimSpace(cell.Text())
+			if number, err = strconv.Atoi(text); err != nil {
+				ess.AddCtxTo("parsing related class number", &err)
+				return false
+			}
+
+			choices = append(choices, number)
+			if chosen[number] {
+				choice = index
+			}
+			return true
+		})
+		if err != nil {
+			return nil, ess.AddCtx("uwquest: parsing related components", err)
+		}
+		if choice == -1 {
+			return nil, &RelatedComponentError{Choices: choices}
+		}
+		form.Set(grid+"$sels$0", strconv.Itoa(choice))
+	}
+	return form, nil
+}
+
+// pageError returns the error message displayed on a PeopleSoft enrollment
+// page, if there is one.
+func pageError(sel *gq.Selection) string {
+	return strings.TrimSpace(sel.Find(`#DERIVED_SASSMSG_ERROR_TEXT`).Text())
+}
+
+func findCartEntry(entries []*CartEntry, classNumber int) *CartEntry {
+	for _, entry := range entries {
+		if entry.Number == classNumber {
+			return entry
+		}
+	}
+	return nil
+}
+
+// parseCart parses the shopping cart table into a set of CartEntries.
+func parseCart(sel *gq.Selection) ([]*CartEntry, error) {
+	sel = sel.Find(`#SSR_REGFORM_VW\$scroll\$0`)
+	if sel.Length() != 1 {
+		return nil, errors.New("could not locate shopping cart table")
+	}
+
+	var (
+		entries []*CartEntry
+		err     error
+	)
+	sel.Find(`[id^="P_CLASS_NAME$"]`).EachWithBreak(
+		func(i int, name *gq.Selection) bool {
+			var index int
+			if index, err = selectionIndex(name); err != nil {
+				return false
+			}
+
+			var entry *CartEntry
+			if entry, err = parseCartRow(sel, index); err != nil {
+				ess.AddCtxTo(fmt.Sprintf("row %d", i), &err)
+				return false
+			}
+			entries = append(entries, entry)
+			return true
+		})
+	return entries, err
+}
+
+// cartClassRegexp matches class names in the shopping cart, i.e.
+// "CS 135-001 (5123)".
+var cartClassRegexp = regexp.MustCompile(`(\S+\s+\S+-\d+)\s*\((\d+)\)`)
+
+func parseCartRow(table *gq.Selection, index int) (*CartEntry, error) {
+	var (
+		entry    = &CartEntry{Index: index}
+		scraper  = indexedScraper{Index: index, Sel: table}
+		sel, err = scraper.Find("P_CLASS_NAME", "class name")
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	matches := cartClassRegexp.FindAllStringSubmatch(
+		strings.Join(textLines(sel), " "), -1)
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("unexpected class name '%s'", sel.Text())
+	}
+	for i, match := range matches {
+		number, err := strconv.Atoi(match[2])
+		if err != nil {
+			return nil, ess.AddCtx("could not parse class number into int", err)
+		}
+		if i == 0 {
+			entry.Name, entry.Number = match[1], number
+			continue
+		}
+		entry.Related = append(entry.Related, number)
+	}
+
+	for _, field := range []struct {
+		ID, Desc string
+		Dest     *string
+	}{
+		{"DERIVED_REGFRM1_SSR_MTG_SCHED_LONG", "class schedule", &entry.Schedule},
+		{"DERIVED_REGFRM1_SSR_MTG_LOC_LONG", "class location", &entry.Location},
+		{"DERIVED_REGFRM1_SSR_INSTR_LONG", "instructor", &entry.Instructor},
+	} {
+		if sel, err = scraper.Find(field.ID, field.Desc); err != nil {
+			return nil, err
+		}
+		*field.Dest = strings.Join(textLines(sel), "\n")
+	}
+
+	if sel, err = scraper.Find("SSR_REGFORM_VW_UNT_TAKEN",
+		"units"); err != nil {
+		return nil, err
+	}
+	if entry.Units, err = parseOptionalFloat(sel.Text()); err != nil {
+		return nil, ess.AddCtx("parsing units string into float", err)
+	}
+
+	sel, err = scraper.Find(`win0divDERIVED_REGFRM1_SSR_STATUS_LONG`,
+		"class status")
+	if err != nil {
+		return nil, err
+	}
+	entry.Status = parseClassStatus(sel)
+
+	// Validation messages are only shown once the cart has been validated.
+	sel, err = scraper.Find("DERIVED_REGFRM1_SS_MESSAGE_LONG",
+		"validation message")
+	if err == nil {
+		entry.Validation = strings.Join(textLines(sel), " ")
+	}
+	return entry, nil
+}
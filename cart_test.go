@@ -0,0 +1,29 @@
+package uwquest_test
+
+import (
+	"testing"
+
+	"github.com/stevenxie/uwquest"
+)
+
+func TestClient_ShoppingCart(t *testing.T) {
+	entries, err := client.ShoppingCart(0)
+	if err != nil {
+		t.Fatalf("Error while fetching shopping cart: %v", err)
+	}
+
+	t.Logf("Got shopping cart for term 0: %v", entries)
+}
+
+func TestClient_RemoveFromCart(t *testing.T) {
+	before := len(client.Audit.Entries())
+
+	// Class 0 is never in the shopping cart, so this does not modify it.
+	if err := client.RemoveFromCart(0, 0); err != uwquest.ErrNotInCart {
+		t.Fatalf("Expected ErrNotInCart when removing class 0, got: %v", err)
+	}
+	if entries := client.Audit.Entries(); len(entries) != before {
+		t.Errorf("Expected nothing to be recorded in the audit log, got: %v",
+			entries[before:])
+	}
+}
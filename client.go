@@ -1,8 +1,10 @@
 package uwquest
 
 import (
+	"errors"
 	"net/http"
 	"net/http/cookiejar"
+	"net/url"
 
 	ess "github.com/unixpickle/essentials"
 )
@@ -17,6 +19,10 @@ type Client struct {
 
 	// Jar is a cookiejar that contains Session's cookies.
 	Jar *cookiejar.Jar
+
+	// Audit records the requests made by the Client that change data on
+	// Quest, such as enrollment changes.
+	Audit *AuditLog
 }
 
 // NewClient returns a new Client.
@@ -30,7 +36,36 @@ func NewClient() (*Client, error) {
 	}
 
 	return &Client{
-		Session: &http.Client{Jar: jar},
+		Session: &http.Client{Jar: jar, CheckRedirect: checkRedirect},
 		Jar:     jar,
+		Audit:   new(AuditLog),
 	}, nil
 }
+
+// questHost is the host that Quest is served from.
+const questHost = "quest.pecs.uwaterloo.ca"
+
+// checkRedirect is the CheckRedirect function of a Client's Session. It stops
+// requests for Quest pages that are redirected to the login page, with
+// ErrSessionExpired.
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= 10 {
+		return errors.New("stopped after 10 redirects")
+	}
+	if isQuestPage(via[0].URL) && isLoginPage(req.URL) {
+		return ErrSessionExpired
+	}
+	return nil
+}
+
+// isQuestPage reports whether u is a page on Quest, other than the pages for
+// logging in and out.
+func isQuestPage(u *url.URL) bool {
+	return u.Host == questHost && u.Query().Get("cmd") == ""
+}
+
+// isLoginPage reports whether u is a page for logging into Quest.
+func isLoginPage(u *url.URL) bool {
+	return u.Host == "idp.uwaterloo.ca" ||
+		(u.Host == questHost && u.Query().Get("cmd") == "login")
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/stevenxie/uwquest/notify"
+	"github.com/stevenxie/uwquest/watch"
+	ess "github.com/unixpickle/essentials"
+)
+
+// Config configures gradewatch.
+type Config struct {
+	User, Pass string
+	StorePath  string
+
+	// StoreDir and StorePassphrase configure a store.Store, which is used
+	// instead of StorePath if StoreDir is set.
+	StoreDir, StorePassphrase string
+
+	Interval time.Duration
+	Terms    int
+	Quiet    *watch.QuietHours
+
+	// Notifiers are the notifiers configured in the environment; new grades
+	// are only logged if there are none.
+	Notifiers []notify.Notifier
+}
+
+// ReadConfig reads a Config from the environment.
+func ReadConfig() (*Config, error) {
+	cfg := &Config{
+		User:            os.Getenv("QUEST_USER"),
+		Pass:            os.Getenv("QUEST_PASS"),
+		StorePath:       os.Getenv("GRADEWATCH_STORE"),
+		StoreDir:        os.Getenv("GRADEWATCH_STORE_DIR"),
+		StorePassphrase: os.Getenv("GRADEWATCH_STORE_PASSPHRASE"),
+		Interval:        15 * time.Minute,
+		Terms:           1,
+	}
+	if cfg.User == "" || cfg.Pass == "" {
+		return nil, errors.New("gradewatch: QUEST_USER and QUEST_PASS must be set")
+	}
+	if cfg.StorePath == "" {
+		cfg.StorePath = "grades.json"
+	}
+
+	var err error
+	if s := os.Getenv("GRADEWATCH_INTERVAL"); s != "" {
+		if cfg.Interval, err = time.ParseDuration(s); err != nil {
+			return nil, ess.AddCtx("gradewatch: parsing GRADEWATCH_INTERVAL", err)
+		}
+	}
+	if s := os.Getenv("GRADEWATCH_TERMS"); s != "" {
+		if cfg.Terms, err = strconv.Atoi(s); err != nil {
+			return nil, ess.AddCtx("gradewatch: parsing GRADEWATCH_TERMS", err)
+		}
+	}
+	if s := os.Getenv("GRADEWATCH_QUIET"); s != "" {
+		if cfg.Quiet, err = parseQuietHours(s); err != nil {
+			return nil, ess.AddCtx("gradewatch: parsing GRADEWATCH_QUIET", err)
+		}
+	}
+
+	cfg.Notifiers = readNotifiers()
+	return cfg, nil
+}
+
+// readNotifiers reads the notifiers that are configured in the environment.
+func readNotifiers() []notify.Notifier {
+	var notifiers []notify.Notifier
+	if addr := os.Getenv("GRADEWATCH_SMTP_ADDR"); addr != "" {
+		notifiers = append(notifiers, &notify.SMTPNotifier{
+			Addr:     addr,
+			Username: os.Getenv("GRADEWATCH_SMTP_USER"),
+			Password: os.Getenv("GRADEWATCH_SMTP_PASS"),
+			From:     os.Getenv("GRADEWATCH_SMTP_FROM"),
+			To:       strings.Split(os.Getenv("GRADEWATCH_SMTP_TO"), ","),
+		})
+	}
+	if url := os.Getenv("GRADEWATCH_WEBHOOK"); url != "" {
+		webhook := &notify.WebhookNotifier{URL: url}
+		if secret := os.Getenv("GRADEWATCH_WEBHOOK_SECRET"); secret != "" {
+			webhook.Secret = []byte(secret)
+		}
+		notifiers = append(notifiers, webhook)
+	}
+	if url := os.Getenv("GRADEWATCH_SLACK"); url != "" {
+		notifiers = append(notifiers, &notify.SlackNotifier{URL: url})
+	}
+	if url := os.Getenv("GRADEWATCH_DISCORD"); url != "" {
+		notifiers = append(notifiers, &notify.DiscordNotifier{URL: url})
+	}
+	if cmd := os.Getenv("GRADEWATCH_EXEC"); cmd != "" {
+		notifiers = append(notifiers, &notify.ExecNotifier{
+			Command: "sh",
+			Args:    []string{"-c", cmd},
+		})
+	}
+	return notifiers
+}
+
+// parseQuietHours parses quiet hours of the form "23-7".
+func parseQuietHours(s string) (*watch.QuietHours, error) {
+	parts := strings.Split(s, "-")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("expected hours of the form 'start-end', got '%s'",
+			s)
+	}
+
+	var hours [2]int
+	for i, part := range parts {
+		hour, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, err
+		}
+		if hour < 0 || hour > 23 {
+			return nil, fmt.Errorf("hour %d is out of range", hour)
+		}
+		hours[i] = hour
+	}
+	return &watch.QuietHours{Start: hours[0], End: hours[1]}, nil
+}
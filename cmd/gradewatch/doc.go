@@ -0,0 +1,36 @@
+// Command gradewatch watches Quest for newly released grades, and reports each
+// one as it comes out.
+//
+// It is configured using the following environment variables (which may also
+// be set in a .env file):
+//
+//	QUEST_USER, QUEST_PASS  Quest credentials (required).
+//	GRADEWATCH_STORE        The file that seen grades are kept in (default
+//	                        "grades.json").
+//	GRADEWATCH_STORE_DIR    A directory to keep grades (and their history) in,
+//	                        instead of GRADEWATCH_STORE (optional).
+//	GRADEWATCH_STORE_PASSPHRASE
+//	                        A passphrase to encrypt GRADEWATCH_STORE_DIR with
+//	                        (optional).
+//	GRADEWATCH_INTERVAL     The time between checks (default "15m").
+//	GRADEWATCH_TERMS        The number of recent terms to watch (default 1).
+//	GRADEWATCH_QUIET        Hours during which Quest is not checked, i.e.
+//	                        "23-7" (optional).
+//
+// New grades are logged, and also sent using each of the following
+// notifiers that is configured:
+//
+//	GRADEWATCH_SMTP_ADDR     Email, sent through this SMTP server (i.e.
+//	                         "smtp.gmail.com:587") using STARTTLS. Also set
+//	                         GRADEWATCH_SMTP_USER, GRADEWATCH_SMTP_PASS,
+//	                         GRADEWATCH_SMTP_FROM, and GRADEWATCH_SMTP_TO (a
+//	                         comma-separated list).
+//	GRADEWATCH_WEBHOOK       A JSON webhook, signed using
+//	                         GRADEWATCH_WEBHOOK_SECRET if it is set.
+//	GRADEWATCH_SLACK         A Slack incoming webhook URL.
+//	GRADEWATCH_DISCORD       A Discord webhook URL.
+//	GRADEWATCH_EXEC          A shell command, which receives each grade as
+//	                         JSON on stdin.
+//
+// It runs until it receives SIGINT or SIGTERM.
+package main
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/stevenxie/uwquest"
+	"github.com/stevenxie/uwquest/notify"
+	"github.com/stevenxie/uwquest/store"
+	"github.com/stevenxie/uwquest/watch"
+	ess "github.com/unixpickle/essentials"
+)
+
+func init() {
+	godotenv.Load()
+}
+
+func main() {
+	cfg, err := ReadConfig()
+	if err != nil {
+		ess.Die("Reading config:", err)
+	}
+
+	client, err := uwquest.NewClient()
+	if err != nil {
+		ess.Die("Creating Quest client:", err)
+	}
+	log.Println("Logging into Quest...")
+	if err = client.Login(cfg.User, cfg.Pass); err != nil {
+		ess.Die("Error logging into Quest:", err)
+	}
+
+	var grades watch.GradeStore = watch.NewFileStore(cfg.StorePath)
+	if cfg.StoreDir != "" {
+		if grades, err = store.Open(cfg.StoreDir, &store.Options{
+			Passphrase: cfg.StorePassphrase,
+		}); err != nil {
+			ess.Die("Opening store:", err)
+		}
+	}
+
+	// Retry each notifier separately. The watcher records which notifiers
+	// have been sent each grade, so that one failing doesn't cause the others
+	// to send duplicates.
+	notifiers := notify.Multi{logNotifier}
+	for _, n := range cfg.Notifiers {
+		notifiers = append(notifiers, &notify.Retry{
+			Notifier: n,
+			Delay:    10 * time.Second,
+		})
+	}
+
+	watcher := &watch.GradeWatcher{
+		Fetcher:  &sessionFetcher{Client: client, User: cfg.User, Pass: cfg.Pass},
+		Store:    grades,
+		Notifier: notifiers,
+		Terms:    cfg.Terms,
+		Interval: cfg.Interval,
+		Quiet:    cfg.Quiet,
+		OnError:  func(err error) { log.Println("Error:", err) },
+	}
+
+	// Stop cleanly upon SIGINT or SIGTERM.
+	ctx, cancel := context.WithCancel(context.Background())
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+	go func() {
+		sig := <-signals
+		log.Printf("Received %s, shutting down...", sig)
+		cancel()
+	}()
+
+	log.Println("Watching for new grades...")
+	if err = watcher.Run(ctx); err != context.Canceled {
+		ess.Die("Error while watching grades:", err)
+	}
+}
+
+// logNotifier logs each event.
+var logNotifier = notify.NotifierFunc(
+	func(_ context.Context, e notify.Event) error {
+		log.Println(e)
+		return nil
+	})
+
+// sessionFetcher is a watch.GradeFetcher that reuses a logged-in Client, and
+// logs in again when its session expires.
+type sessionFetcher struct {
+	Client     *uwquest.Client
+	User, Pass string
+}
+
+func (sf *sessionFetcher) Terms() (terms []*uwquest.Term, err error) {
+	err = sf.Client.RetryExpired(sf.User, sf.Pass, func() error {
+		terms, err = sf.Client.Terms()
+		return err
+	})
+	return terms, err
+}
+
+func (sf *sessionFetcher) Grades(termIndex int) (grades *uwquest.TermGrades,
+	err error) {
+	err = sf.Client.RetryExpired(sf.User, sf.Pass, func() error {
+		grades, err = sf.Client.Grades(termIndex)
+		return err
+	})
+	return grades, err
+}
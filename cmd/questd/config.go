@@ -0,0 +1,66 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/stevenxie/uwquest/server"
+	ess "github.com/unixpickle/essentials"
+)
+
+// Config configures questd.
+type Config struct {
+	User, Pass string
+	Addr       string
+	TTL        time.Duration
+
+	// StoreDir and StorePassphrase configure a store.Store, which enables the
+	// /events stream if StoreDir is set.
+	StoreDir, StorePassphrase string
+
+	// WatchInterval and WatchTerms configure the watchers that produce the
+	// events in the /events stream.
+	WatchInterval time.Duration
+	WatchTerms    int
+}
+
+// ReadConfig reads a Config from the environment.
+func ReadConfig() (*Config, error) {
+	cfg := &Config{
+		User: os.Getenv("QUEST_USER"),
+		Pass: os.Getenv("QUEST_PASS"),
+		Addr: os.Getenv("QUESTD_ADDR"),
+		TTL:  server.DefaultTTL,
+
+		StoreDir:        os.Getenv("QUESTD_STORE_DIR"),
+		StorePassphrase: os.Getenv("QUESTD_STORE_PASSPHRASE"),
+		WatchInterval:   15 * time.Minute,
+		WatchTerms:      1,
+	}
+	if cfg.User == "" || cfg.Pass == "" {
+		return nil, errors.New("questd: QUEST_USER and QUEST_PASS must be set")
+	}
+	if cfg.Addr == "" {
+		cfg.Addr = ":8080"
+	}
+
+	var err error
+	if s := os.Getenv("QUESTD_TTL"); s != "" {
+		if cfg.TTL, err = time.ParseDuration(s); err != nil {
+			return nil, ess.AddCtx("questd: parsing QUESTD_TTL", err)
+		}
+	}
+	if s := os.Getenv("QUESTD_WATCH_INTERVAL"); s != "" {
+		if cfg.WatchInterval, err = time.ParseDuration(s); err != nil {
+			return nil, ess.AddCtx("questd: parsing QUESTD_WATCH_INTERVAL", err)
+		}
+	}
+	if s := os.Getenv("QUESTD_WATCH_TERMS"); s != "" {
+		if cfg.WatchTerms, err = strconv.Atoi(s); err != nil {
+			return nil, ess.AddCtx("questd: parsing QUESTD_WATCH_TERMS", err)
+		}
+	}
+	return cfg, nil
+}
@@ -0,0 +1,23 @@
+// Command questd serves a student's data from Quest as JSON over HTTP (see
+// package server), for use by programs such as web dashboards.
+//
+// It is configured using the following environment variables (which may also
+// be set in a .env file):
+//
+//	QUEST_USER, QUEST_PASS  Quest credentials (required).
+//	QUESTD_ADDR             The address to listen on (default ":8080").
+//	QUESTD_TTL              The time that responses are cached for (default
+//	                        "5m").
+//	QUESTD_STORE_DIR        A directory to keep schedules, grades, and events
+//	                        in (optional). If it is set, questd watches for
+//	                        changes to them, and streams them from /events.
+//	QUESTD_STORE_PASSPHRASE
+//	                        A passphrase to encrypt QUESTD_STORE_DIR with
+//	                        (optional).
+//	QUESTD_WATCH_INTERVAL   The time between checks for changes (default
+//	                        "15m").
+//	QUESTD_WATCH_TERMS      The number of recent terms to watch (default 1).
+//
+// It logs each request, and shuts down gracefully (finishing the requests in
+// progress) when it receives SIGINT or SIGTERM.
+package main
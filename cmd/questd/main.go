@@ -0,0 +1,166 @@
+package main
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/stevenxie/uwquest"
+	"github.com/stevenxie/uwquest/server"
+	"github.com/stevenxie/uwquest/store"
+	"github.com/stevenxie/uwquest/watch"
+	ess "github.com/unixpickle/essentials"
+)
+
+// shutdownTimeout is the time that questd waits for requests in progress to
+// finish when shutting down.
+const shutdownTimeout = 30 * time.Second
+
+func init() {
+	godotenv.Load()
+}
+
+func main() {
+	cfg, err := ReadConfig()
+	if err != nil {
+		ess.Die("Reading config:", err)
+	}
+
+	client, err := uwquest.NewClient()
+	if err != nil {
+		ess.Die("Creating Quest client:", err)
+	}
+	log.Println("Logging into Quest...")
+	if err = client.Login(cfg.User, cfg.Pass); err != nil {
+		ess.Die("Error logging into Quest:", err)
+	}
+
+	quest := &sessionClient{Client: client, User: cfg.User, Pass: cfg.Pass}
+	api := &server.Server{Quest: quest, TTL: cfg.TTL}
+	srv := &http.Server{Addr: cfg.Addr, Handler: server.Logged(api, nil)}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if cfg.StoreDir != "" {
+		s, err := store.Open(cfg.StoreDir, &store.Options{
+			Passphrase: cfg.StorePassphrase,
+		})
+		if err != nil {
+			ess.Die("Opening store:", err)
+		}
+		api.Events = &server.Broker{Log: s}
+		srv.RegisterOnShutdown(api.Events.Close)
+		startWatchers(ctx, cfg, quest, s, api.Events)
+	}
+
+	// Shut down gracefully upon SIGINT or SIGTERM.
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		sig := <-signals
+		log.Printf("Received %s, shutting down...", sig)
+		cancel()
+
+		shutdownCtx, stop := context.WithTimeout(context.Background(),
+			shutdownTimeout)
+		defer stop()
+		if err := srv.Shutdown(shutdownCtx); err != nil {
+			log.Println("Error while shutting down:", err)
+		}
+	}()
+
+	log.Printf("Listening on %s...", cfg.Addr)
+	if err = srv.ListenAndServe(); err != http.ErrServerClosed {
+		ess.Die("Error while serving:", err)
+	}
+	<-done
+}
+
+// startWatchers starts watching for changes to grades and schedules in the
+// background, until ctx is done. Changes are saved in s, and sent to broker.
+func startWatchers(ctx context.Context, cfg *Config, quest *sessionClient,
+	s *store.Store, broker *server.Broker) {
+	onError := func(err error) { log.Println("Error while watching:", err) }
+	grades := &watch.GradeWatcher{
+		Fetcher:  quest,
+		Store:    s,
+		Notifier: broker,
+		Terms:    cfg.WatchTerms,
+		Interval: cfg.WatchInterval,
+		OnError:  onError,
+	}
+	schedules := &watch.ScheduleWatcher{
+		Fetcher:  quest,
+		Store:    s,
+		Notifier: broker,
+		Terms:    cfg.WatchTerms,
+		Interval: cfg.WatchInterval,
+		OnError:  onError,
+	}
+	go grades.Run(ctx)
+	go schedules.Run(ctx)
+}
+
+// sessionClient is a server.Quest (and a watch.GradeFetcher and
+// watch.ScheduleFetcher) that reuses a logged-in Client, and logs in again
+// when its session expires.
+//
+// It makes one request at a time, since it is shared by the server and the
+// watchers.
+type sessionClient struct {
+	Client     *uwquest.Client
+	User, Pass string
+
+	mu sync.Mutex
+}
+
+func (sc *sessionClient) Terms() (terms []*uwquest.Term, err error) {
+	err = sc.retry(func() error {
+		terms, err = sc.Client.Terms()
+		return err
+	})
+	return terms, err
+}
+
+func (sc *sessionClient) TermsWithSchedule() (terms []*uwquest.Term,
+	err error) {
+	err = sc.retry(func() error {
+		terms, err = sc.Client.TermsWithSchedule()
+		return err
+	})
+	return terms, err
+}
+
+func (sc *sessionClient) Grades(termIndex int) (grades *uwquest.TermGrades,
+	err error) {
+	err = sc.retry(func() error {
+		grades, err = sc.Client.Grades(termIndex)
+		return err
+	})
+	return grades, err
+}
+
+func (sc *sessionClient) Schedules(termIndex int) (
+	schedules []*uwquest.CourseSchedule, err error) {
+	err = sc.retry(func() error {
+		schedules, err = sc.Client.Schedules(termIndex)
+		return err
+	})
+	return schedules, err
+}
+
+// retry calls fn, logging in again if the session has expired (see
+// uwquest.Client.RetryExpired).
+func (sc *sessionClient) retry(fn func() error) error {
+	sc.mu.Lock()
+	defer sc.mu.Unlock()
+	return sc.Client.RetryExpired(sc.User, sc.Pass, fn)
+}
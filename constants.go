@@ -2,8 +2,26 @@ package uwquest
 
 // Quest endpoint URLs.
 const (
-	BaseURL          = "https://quest.pecs.uwaterloo.ca/psc/SS/ACADEMIC/SA/c/"
-	StudentCenterURL = BaseURL + "SA_LEARNER_SERVICES.SSS_STUDENT_CENTER.GBL"
-	GradesURL        = BaseURL + "UW_SS_MENU.UW_SSR_SSENRL_GRDE.GBL"
-	SchedulesURL     = BaseURL + "SA_LEARNER_SERVICES.SSR_SSENRL_LIST.GBL"
+	BaseURL            = "https://quest.pecs.uwaterloo.ca/psc/SS/ACADEMIC/SA/c/"
+	StudentCenterURL   = BaseURL + "SA_LEARNER_SERVICES.SSS_STUDENT_CENTER.GBL"
+	GradesURL          = BaseURL + "UW_SS_MENU.UW_SSR_SSENRL_GRDE.GBL"
+	SchedulesURL       = BaseURL + "SA_LEARNER_SERVICES.SSR_SSENRL_LIST.GBL"
+	ClassSearchURL     = BaseURL + "SA_LEARNER_SERVICES.CLASS_SEARCH.GBL"
+	ShoppingCartURL    = BaseURL + "SA_LEARNER_SERVICES.SSR_SSENRL_CART.GBL"
+	DropURL            = BaseURL + "SA_LEARNER_SERVICES.SSR_SSENRL_DROP.GBL"
+	SwapURL            = BaseURL + "SA_LEARNER_SERVICES.SSR_SSENRL_SWAP.GBL"
+	EnrollmentDatesURL = BaseURL + "SA_LEARNER_SERVICES.SSR_SSENRL_APPT.GBL"
+	AccountSummaryURL  = BaseURL + "SA_LEARNER_SERVICES.SSF_SS_ACCT_SUMMARY.GBL"
+	AccountActivityURL = BaseURL + "SA_LEARNER_SERVICES.SSF_SS_ACTIVITY.GBL"
+	TaxReceiptsURL     = BaseURL + "SA_LEARNER_SERVICES.SSF_SS_T2202A.GBL"
+	AcademicProgramURL = BaseURL + "UW_SS_MENU.UW_SS_ACAD_PROG.GBL"
+	AdvisementURL      = BaseURL + "SA_LEARNER_SERVICES.SAA_SS_DPR_ADB.GBL"
+	CourseHistoryURL   = BaseURL + "SA_LEARNER_SERVICES.SSS_MY_CRSEHIST.GBL"
+	TransferCreditURL  = BaseURL + "SA_LEARNER_SERVICES.SSS_TRANSFER_CRDT.GBL"
+
+	NamesURL             = BaseURL + "CC_PORTFOLIO.SS_CC_NAMES.GBL"
+	AddressesURL         = BaseURL + "CC_PORTFOLIO.SS_CC_ADDRESSES.GBL"
+	PhonesURL            = BaseURL + "CC_PORTFOLIO.SS_CC_PERS_PHONE.GBL"
+	EmailsURL            = BaseURL + "CC_PORTFOLIO.SS_CC_EMAIL_ADDR.GBL"
+	EmergencyContactsURL = BaseURL + "CC_PORTFOLIO.SS_CC_EMRG_CNTCT.GBL"
 )
@@ -0,0 +1,232 @@
+// Package diff compares two fetches of the same data from Quest, and reports
+// the differences between them as typed Changes.
+//
+// Records are matched by their identity (i.e. a course's name, or a class's
+// number) rather than by their position, so rows that Quest reorders are not
+// reported as changes.
+package diff
+
+import (
+	"fmt"
+
+	"github.com/stevenxie/uwquest"
+)
+
+// Grades returns the changes from prev to cur, which are the grades of the
+// courses in a term. It reports grades that are posted, and grades that
+// change; grades that are removed are not reported.
+func Grades(term string, prev, cur []*uwquest.CourseGrade) []Change {
+	old := make(map[string]string, len(prev))
+	for _, cg := range prev {
+		old[cg.Name] = cg.Grade
+	}
+
+	var changes []Change
+	for _, cg := range cur {
+		key := Key{Term: term, Course: cg.Name}
+		switch grade := old[cg.Name]; {
+		case cg.Grade == "" || cg.Grade == grade:
+			continue
+		case grade == "":
+			changes = append(changes, &GradePosted{
+				Key:         key,
+				Grade:       cg.Grade,
+				GradePoints: cg.GradePoints,
+			})
+		default:
+			changes = append(changes, &GradeChanged{
+				Key:         key,
+				Old:         grade,
+				New:         cg.Grade,
+				GradePoints: cg.GradePoints,
+			})
+		}
+	}
+	return changes
+}
+
+// Schedules returns the changes from prev to cur, which are the course
+// schedules of a term. It reports classes that are added or dropped, changes
+// to their rooms, times, and instructors, and changes to the enrollment
+// status of each course.
+func Schedules(term string, prev, cur []*uwquest.CourseSchedule) []Change {
+	oldCourses := make(map[string]*uwquest.CourseSchedule, len(prev))
+	for _, cs := range prev {
+		oldCourses[cs.Name] = cs
+	}
+
+	var (
+		changes []Change
+		seen    = make(map[string]bool, len(cur))
+	)
+	for _, cs := range cur {
+		seen[cs.Name] = true
+		key := Key{Term: term, Course: cs.Name}
+		old := oldCourses[cs.Name]
+		if old == nil {
+			old = new(uwquest.CourseSchedule)
+		} else if old.Status != cs.Status {
+			changes = append(changes, &StatusChanged{
+				Key: key,
+				Old: old.Status,
+				New: cs.Status,
+			})
+		}
+		changes = append(changes, classes(key, old.Classes, cs.Classes)...)
+	}
+
+	// Report the classes of courses that are gone altogether.
+	for _, cs := range prev {
+		if seen[cs.Name] {
+			continue
+		}
+		key := Key{Term: term, Course: cs.Name}
+		changes = append(changes, classes(key, cs.Classes, nil)...)
+	}
+	return changes
+}
+
+// classes returns the changes from prev to cur, which are the classes of a
+// course.
+func classes(key Key, prev, cur []*uwquest.Class) []Change {
+	var (
+		prevKeys   = classKeys(prev)
+		curKeys    = classKeys(cur)
+		oldClasses = make(map[string]*uwquest.Class, len(prev))
+		current    = make(map[string]bool, len(cur))
+	)
+	for i, c := range prev {
+		oldClasses[prevKeys[i]] = c
+	}
+
+	var changes []Change
+	for i, c := range cur {
+		current[curKeys[i]] = true
+		old, ok := oldClasses[curKeys[i]]
+		if !ok {
+			changes = append(changes, &ClassAdded{Key: key, Class: c})
+			continue
+		}
+		for _, field := range []struct {
+			Field    ClassField
+			Old, New string
+		}{
+			{RoomField, old.Location, c.Location},
+			{TimeField, old.Schedule, c.Schedule},
+			{InstructorField, old.Instructor, c.Instructor},
+		} {
+			if field.Old == field.New {
+				continue
+			}
+			changes = append(changes, &ClassChanged{
+				Key:   key,
+				Class: c,
+				Field: field.Field,
+				Old:   field.Old,
+				New:   field.New,
+			})
+		}
+	}
+	for i, c := range prev {
+		if !current[prevKeys[i]] {
+			changes = append(changes, &ClassDropped{Key: key, Class: c})
+		}
+	}
+	return changes
+}
+
+// classKeys returns the identity of each class. A class is identified by its
+// number, and by its position among the classes with the same number (since a
+// class that meets at several times is listed once for each time).
+func classKeys(classes []*uwquest.Class) []string {
+	var (
+		keys   = make([]string, len(classes))
+		counts = make(map[int]int)
+	)
+	for i, c := range classes {
+		keys[i] = fmt.Sprintf("%d/%d", c.Number, counts[c.Number])
+		counts[c.Number]++
+	}
+	return keys
+}
+
+// Terms returns the changes from prev to cur, which are the terms that are
+// available on Quest. Terms are identified by their names and careers.
+func Terms(prev, cur []*uwquest.Term) []Change {
+	termKey := func(t *uwquest.Term) string { return t.Name + "/" + t.Career }
+	old := make(map[string]bool, len(prev))
+	for _, t := range prev {
+		old[termKey(t)] = true
+	}
+	now := make(map[string]bool, len(cur))
+	for _, t := range cur {
+		now[termKey(t)] = true
+	}
+
+	var changes []Change
+	for _, t := range cur {
+		if !old[termKey(t)] {
+			changes = append(changes, &TermAdded{
+				Key:    Key{Term: t.Name},
+				Career: t.Career,
+			})
+		}
+	}
+	for _, t := range prev {
+		if !now[termKey(t)] {
+			changes = append(changes, &TermRemoved{
+				Key:    Key{Term: t.Name},
+				Career: t.Career,
+			})
+		}
+	}
+	return changes
+}
+
+// Sections returns the changes from prev to cur, which are the results of
+// searching for class sections in a term. It reports sections that were full
+// and have open seats, and sections whose wait lists move. Sections are
+// identified by their class numbers; those that are not in both prev and cur
+// are ignored.
+func Sections(term string, prev, cur []*uwquest.ClassSection) []Change {
+	old := make(map[int]*uwquest.ClassSection, len(prev))
+	for _, s := range prev {
+		old[s.Number] = s
+	}
+
+	var changes []Change
+	for _, s := range cur {
+		p, ok := old[s.Number]
+		if !ok {
+			continue
+		}
+		event := &SeatEvent{
+			Key:      Key{Term: term, Course: s.Course},
+			Section:  s,
+			Previous: p,
+		}
+		switch {
+		case !HasSeats(p) && HasSeats(s):
+			event.Kind = SeatOpened
+		case p.WaitlistTotal != nil && s.WaitlistTotal != nil &&
+			*p.WaitlistTotal != *s.WaitlistTotal:
+			event.Kind = WaitlistMoved
+		default:
+			continue
+		}
+		changes = append(changes, event)
+	}
+	return changes
+}
+
+// HasSeats reports whether a class section has open seats, using its status,
+// or its enrollment totals if its status is unknown.
+func HasSeats(s *uwquest.ClassSection) bool {
+	switch s.Status {
+	case uwquest.OpenStatus:
+		return true
+	case uwquest.ClosedStatus, uwquest.WaitlistStatus:
+		return false
+	}
+	return s.Capacity != nil && s.Total != nil && *s.Total < *s.Capacity
+}
@@ -0,0 +1,170 @@
+package diff_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stevenxie/uwquest"
+	"github.com/stevenxie/uwquest/diff"
+)
+
+func TestGrades(t *testing.T) {
+	points := float32(4)
+	prev := []*uwquest.CourseGrade{
+		{Name: "CS 135", Grade: "80"},
+		{Name: "MATH 135"},
+		{Name: "ENGL 109", Grade: "75"},
+	}
+	// Courses are matched by name, even when Quest reorders them.
+	cur := []*uwquest.CourseGrade{
+		{Name: "ENGL 109"},
+		{Name: "MATH 135", Grade: "90", GradePoints: &points},
+		{Name: "CS 135", Grade: "85"},
+	}
+
+	expected := []diff.Change{
+		&diff.GradePosted{
+			Key:         diff.Key{Term: "Fall 2018", Course: "MATH 135"},
+			Grade:       "90",
+			GradePoints: &points,
+		},
+		&diff.GradeChanged{
+			Key: diff.Key{Term: "Fall 2018", Course: "CS 135"},
+			Old: "80",
+			New: "85",
+		},
+	}
+	if changes := diff.Grades("Fall 2018", prev, cur); !reflect.DeepEqual(
+		changes, expected) {
+		t.Errorf("Expected %v, got: %v", expected, changes)
+	}
+	if changes := diff.Grades("Fall 2018", cur, cur); len(changes) != 0 {
+		t.Errorf("Expected no changes, got: %v", changes)
+	}
+}
+
+func TestSchedules(t *testing.T) {
+	prev := []*uwquest.CourseSchedule{
+		{
+			Name:   "CS 135",
+			Status: uwquest.Enrolled,
+			Classes: []*uwquest.Class{
+				{Number: 5123, Component: "LEC", Section: 1, Location: "MC 2065",
+					Schedule: "MWF 10:30AM - 11:20AM", Instructor: "Staff"},
+				{Number: 5124, Component: "TUT", Section: 101},
+			},
+		},
+		{
+			Name:    "MATH 135",
+			Status:  uwquest.Waiting,
+			Classes: []*uwquest.Class{{Number: 6001, Component: "LEC"}},
+		},
+		{
+			Name:    "ENGL 109",
+			Status:  uwquest.Enrolled,
+			Classes: []*uwquest.Class{{Number: 7001, Component: "LEC"}},
+		},
+	}
+	cur := []*uwquest.CourseSchedule{
+		{
+			Name:    "MATH 135",
+			Status:  uwquest.Enrolled,
+			Classes: []*uwquest.Class{{Number: 6001, Component: "LEC"}},
+		},
+		{
+			Name:   "CS 135",
+			Status: uwquest.Enrolled,
+			Classes: []*uwquest.Class{
+				{Number: 5125, Component: "TUT", Section: 102},
+				{Number: 5123, Component: "LEC", Section: 1, Location: "MC 4020",
+					Schedule: "MWF 10:30AM - 11:20AM", Instructor: "Jane Doe"},
+			},
+		},
+	}
+
+	changes := diff.Schedules("Fall 2018", prev, cur)
+	expected := []string{
+		"status_changed MATH 135",
+		"class_added CS 135",
+		"room_changed CS 135",
+		"instructor_changed CS 135",
+		"class_dropped CS 135",
+		"class_dropped ENGL 109",
+	}
+	if len(changes) != len(expected) {
+		t.Fatalf("Expected %d changes, got: %v", len(expected), changes)
+	}
+	for i, c := range changes {
+		if actual := c.EventType() + " " + c.ChangeKey().Course; actual !=
+			expected[i] {
+			t.Errorf("Change %d: expected %s, got: %s", i, expected[i], c)
+		}
+	}
+
+	room := changes[2].(*diff.ClassChanged)
+	if room.Old != "MC 2065" || room.New != "MC 4020" {
+		t.Errorf("Unexpected room change: %s", room)
+	}
+	if dropped := changes[4].(*diff.ClassDropped); dropped.Class.Number != 5124 {
+		t.Errorf("Expected class 5124 to be dropped, got: %s", dropped)
+	}
+}
+
+func TestTerms(t *testing.T) {
+	prev := []*uwquest.Term{
+		{Index: 0, Name: "Spring 2018", Career: "Undergraduate"},
+		{Index: 1, Name: "Fall 2018", Career: "Undergraduate"},
+	}
+	cur := []*uwquest.Term{
+		{Index: 0, Name: "Fall 2018", Career: "Undergraduate"},
+		{Index: 1, Name: "Winter 2019", Career: "Undergraduate"},
+	}
+
+	expected := []diff.Change{
+		&diff.TermAdded{Key: diff.Key{Term: "Winter 2019"},
+			Career: "Undergraduate"},
+		&diff.TermRemoved{Key: diff.Key{Term: "Spring 2018"},
+			Career: "Undergraduate"},
+	}
+	if changes := diff.Terms(prev, cur); !reflect.DeepEqual(changes,
+		expected) {
+		t.Errorf("Expected %v, got: %v", expected, changes)
+	}
+}
+
+func TestSections(t *testing.T) {
+	section := func(number int, status uwquest.ClassStatus,
+		waitlist int) *uwquest.ClassSection {
+		return &uwquest.ClassSection{
+			Course:        "CS 135",
+			Number:        number,
+			Section:       1,
+			Component:     "LEC",
+			Status:        status,
+			WaitlistTotal: &waitlist,
+		}
+	}
+	prev := []*uwquest.ClassSection{
+		section(1, uwquest.ClosedStatus, 0),
+		section(2, uwquest.ClosedStatus, 5),
+		section(3, uwquest.OpenStatus, 0),
+	}
+	cur := []*uwquest.ClassSection{
+		section(3, uwquest.OpenStatus, 0),
+		section(2, uwquest.ClosedStatus, 4),
+		section(1, uwquest.OpenStatus, 0),
+		section(4, uwquest.OpenStatus, 0), // new sections are ignored
+	}
+
+	changes := diff.Sections("Fall 2018", prev, cur)
+	if len(changes) != 2 {
+		t.Fatalf("Expected 2 changes, got: %v", changes)
+	}
+	for i, kind := range []diff.SeatEventKind{diff.WaitlistMoved,
+		diff.SeatOpened} {
+		if e, ok := changes[i].(*diff.SeatEvent); !ok || e.Kind != kind {
+			t.Errorf("Change %d: expected a %s event, got: %s", i, kind,
+				changes[i])
+		}
+	}
+}
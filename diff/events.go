@@ -0,0 +1,237 @@
+package diff
+
+import (
+	"fmt"
+
+	"github.com/stevenxie/uwquest"
+	"github.com/stevenxie/uwquest/notify"
+)
+
+// A Key identifies what a Change is about.
+type Key struct {
+	Term   string // i.e. "Fall 2018"
+	Course string // i.e. "CS 135"; blank for changes to terms
+}
+
+// ChangeKey returns k. It lets changes that embed a Key implement Change.
+func (k Key) ChangeKey() Key { return k }
+
+// A Change is a difference between two fetches of the same data. Changes are
+// notify.Events, so they can be delivered by any notify.Notifier.
+type Change interface {
+	notify.Event
+	ChangeKey() Key
+}
+
+// A GradePosted is a grade that has been posted for a course that had no
+// grade.
+type GradePosted struct {
+	Key
+	Grade       string
+	GradePoints *float32 // may be nil
+}
+
+// EventType implements notify.Event.
+func (gp *GradePosted) EventType() string { return "grade_posted" }
+
+func (gp *GradePosted) String() string {
+	return fmt.Sprintf("New grade for %s (%s): %s", gp.Course, gp.Term,
+		gp.Grade)
+}
+
+// A GradeChanged is a change to a course's grade.
+type GradeChanged struct {
+	Key
+	Old, New    string
+	GradePoints *float32 // the new grade points; may be nil
+}
+
+// EventType implements notify.Event.
+func (gc *GradeChanged) EventType() string { return "grade_changed" }
+
+func (gc *GradeChanged) String() string {
+	return fmt.Sprintf("Grade for %s (%s) changed from %s to %s", gc.Course,
+		gc.Term, gc.Old, gc.New)
+}
+
+// A ClassAdded is a class that has been added to a student's schedule.
+type ClassAdded struct {
+	Key
+	Class *uwquest.Class
+}
+
+// EventType implements notify.Event.
+func (ca *ClassAdded) EventType() string { return "class_added" }
+
+func (ca *ClassAdded) String() string {
+	return fmt.Sprintf("Added %s (%s)", describeClass(ca.Course, ca.Class),
+		ca.Term)
+}
+
+// A ClassDropped is a class that has been removed from a student's schedule.
+type ClassDropped struct {
+	Key
+	Class *uwquest.Class
+}
+
+// EventType implements notify.Event.
+func (cd *ClassDropped) EventType() string { return "class_dropped" }
+
+func (cd *ClassDropped) String() string {
+	return fmt.Sprintf("Dropped %s (%s)", describeClass(cd.Course, cd.Class),
+		cd.Term)
+}
+
+func describeClass(course string, c *uwquest.Class) string {
+	return fmt.Sprintf("%s %s %03d (%d)", course, c.Component, c.Section,
+		c.Number)
+}
+
+// ClassField is a field of a class that a ClassChanged reports a change to.
+type ClassField int
+
+// The fields of a class that are compared.
+const (
+	RoomField ClassField = iota
+	TimeField
+	InstructorField
+)
+
+func (f ClassField) String() string {
+	switch f {
+	case RoomField:
+		return "room"
+	case TimeField:
+		return "time"
+	case InstructorField:
+		return "instructor"
+	default:
+		return fmt.Sprintf("ClassField(%d)", int(f))
+	}
+}
+
+// A ClassChanged is a change to the room, time, or instructor of a class.
+type ClassChanged struct {
+	Key
+	Class    *uwquest.Class // the class after the change
+	Field    ClassField
+	Old, New string
+}
+
+// EventType implements notify.Event, i.e. "room_changed".
+func (cc *ClassChanged) EventType() string {
+	return cc.Field.String() + "_changed"
+}
+
+func (cc *ClassChanged) String() string {
+	return fmt.Sprintf("The %s of %s (%s) changed from '%s' to '%s'", cc.Field,
+		describeClass(cc.Course, cc.Class), cc.Term, cc.Old, cc.New)
+}
+
+// A StatusChanged is a change to a student's enrollment status in a course.
+type StatusChanged struct {
+	Key
+	Old, New uwquest.EnrollmentStatus
+}
+
+// EventType implements notify.Event.
+func (sc *StatusChanged) EventType() string { return "status_changed" }
+
+func (sc *StatusChanged) String() string {
+	return fmt.Sprintf("Status of %s (%s) changed from %s to %s", sc.Course,
+		sc.Term, sc.Old, sc.New)
+}
+
+// A TermAdded is a term that has become available on Quest.
+type TermAdded struct {
+	Key
+	Career string
+}
+
+// EventType implements notify.Event.
+func (ta *TermAdded) EventType() string { return "term_added" }
+
+func (ta *TermAdded) String() string {
+	return fmt.Sprintf("%s (%s) is now available", ta.Term, ta.Career)
+}
+
+// A TermRemoved is a term that is no longer available on Quest.
+type TermRemoved struct {
+	Key
+	Career string
+}
+
+// EventType implements notify.Event.
+func (tr *TermRemoved) EventType() string { return "term_removed" }
+
+func (tr *TermRemoved) String() string {
+	return fmt.Sprintf("%s (%s) is no longer available", tr.Term, tr.Career)
+}
+
+// SeatEventKind is the kind of change that a SeatEvent reports.
+type SeatEventKind int
+
+// The kinds of seat events.
+const (
+	// SeatOpened is when a class that was full has open seats.
+	SeatOpened SeatEventKind = iota
+
+	// WaitlistMoved is when the number of students on a class's wait list
+	// changes.
+	WaitlistMoved
+)
+
+func (k SeatEventKind) String() string {
+	switch k {
+	case SeatOpened:
+		return "SeatOpened"
+	case WaitlistMoved:
+		return "WaitlistMoved"
+	default:
+		return fmt.Sprintf("SeatEventKind(%d)", int(k))
+	}
+}
+
+// A SeatEvent is a change in the enrollment of a class section.
+type SeatEvent struct {
+	Key
+	Kind     SeatEventKind
+	Section  *uwquest.ClassSection
+	Previous *uwquest.ClassSection // the section before the change
+}
+
+// EventType implements notify.Event.
+func (e *SeatEvent) EventType() string {
+	switch e.Kind {
+	case SeatOpened:
+		return "seat_opened"
+	case WaitlistMoved:
+		return "waitlist_moved"
+	default:
+		return "seat_event"
+	}
+}
+
+// String describes e for a person, i.e. "CS 135 LEC 001 (5123) has open
+// seats for Fall 2018".
+func (e *SeatEvent) String() string {
+	section := fmt.Sprintf("%s %s %03d (%d)", e.Section.Course,
+		e.Section.Component, e.Section.Section, e.Section.Number)
+	switch e.Kind {
+	case SeatOpened:
+		return fmt.Sprintf("%s has open seats for %s", section, e.Term)
+	case WaitlistMoved:
+		return fmt.Sprintf("%s wait list changed from %d to %d for %s", section,
+			derefInt(e.Previous.WaitlistTotal), derefInt(e.Section.WaitlistTotal),
+			e.Term)
+	default:
+		return fmt.Sprintf("%s changed for %s", section, e.Term)
+	}
+}
+
+func derefInt(i *int) int {
+	if i == nil {
+		return 0
+	}
+	return *i
+}
@@ -0,0 +1,414 @@
+package uwquest
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	gq "github.com/PuerkitoBio/goquery"
+	ess "github.com/unixpickle/essentials"
+)
+
+// EnrollmentAction is the kind of change that an EnrollmentPlan makes.
+type EnrollmentAction int
+
+// The enrollment actions that Quest supports.
+const (
+	EnrollAction EnrollmentAction = iota
+	DropAction
+	SwapAction
+)
+
+func (a EnrollmentAction) String() string {
+	switch a {
+	case EnrollAction:
+		return "Enroll"
+	case DropAction:
+		return "Drop"
+	case SwapAction:
+		return "Swap"
+	default:
+		return fmt.Sprintf("EnrollmentAction(%d)", int(a))
+	}
+}
+
+// An EnrollmentPlan is an enrollment change that Quest has validated, but which
+// has not yet been submitted. Plans are submitted using Client.Apply.
+//
+// Since PeopleSoft tracks the state of each session's pages, a plan must be
+// applied before the Client makes any other requests to Quest.
+type EnrollmentPlan struct {
+	Action    EnrollmentAction
+	TermIndex int
+	Classes   []int // the class numbers that the plan affects
+
+	// Messages are the per-class messages shown on Quest's confirmation page.
+	Messages []*EnrollmentMessage
+
+	url  string
+	page *gq.Document
+
+	mu      sync.Mutex // guards applied
+	applied bool
+}
+
+func (ep *EnrollmentPlan) String() string {
+	return fmt.Sprintf("EnrollmentPlan{Action: %s, TermIndex: %d, Classes: %v, "+
+		"Messages: %v}", ep.Action, ep.TermIndex, ep.Classes, ep.Messages)
+}
+
+// An EnrollmentMessage is a message about a particular class shown during
+// enrollment.
+type EnrollmentMessage struct {
+	Class   string // i.e. "CS 135-001"
+	Message string
+}
+
+func (em *EnrollmentMessage) String() string {
+	return fmt.Sprintf("EnrollmentMessage{Class: %s, Message: %s}", em.Class,
+		em.Message)
+}
+
+// An EnrollmentResult is the result of applying an EnrollmentPlan to a
+// particular class.
+type EnrollmentResult struct {
+	Class   string // i.e. "CS 135-001"
+	Success bool
+	Message string
+}
+
+func (er *EnrollmentResult) String() string {
+	return fmt.Sprintf("EnrollmentResult{Class: %s, Success: %t, Message: %s}",
+		er.Class, er.Success, er.Message)
+}
+
+// Errors returned by Apply.
+var (
+	ErrPlanApplied = errors.New("uwquest: enrollment plan was already " +
+		"applied")
+	ErrPlanIncomplete = errors.New("uwquest: enrollment plan has no " +
+		"confirmation page (plans must be made by PlanEnroll, PlanDrop, or " +
+		"PlanSwap)")
+)
+
+// EnrollOptions are the enrollment preferences to set for classes in the
+// shopping cart when planning to enroll in them. Classes that aren't
+// mentioned keep the preferences that they were added to the cart with.
+type EnrollOptions struct {
+	// PermissionNumbers are the permission numbers to enroll with, by class
+	// number.
+	PermissionNumbers map[int]int
+
+	// Waitlist are the class numbers of the classes whose waitlists to join if
+	// they are full.
+	Waitlist []int
+}
+
+// PlanEnroll plans enrollment in classes from the shopping cart for a
+// particular term, after setting their enrollment preferences using opts
+// (which may be nil).
+//
+// If no class numbers are given, it plans enrollment in every class in the
+// shopping cart.
+func (c *Client) PlanEnroll(termIndex int, opts *EnrollOptions,
+	classNumbers ...int) (*EnrollmentPlan, error) {
+	page, err := c.selectTerm(ShoppingCartURL, termIndex)
+	if err != nil {
+		return nil, ess.AddCtx("uwquest: fetching shopping cart page", err)
+	}
+	entries, err := parseCart(page.Selection)
+	if err != nil {
+		return nil, ess.AddCtx("uwquest: parsing shopping cart", err)
+	}
+
+	// Set the enrollment preferences of the classes in opts, in the order that
+	// they appear in the cart.
+	if opts != nil {
+		waitlist := make(map[int]bool, len(opts.Waitlist))
+		for _, number := range opts.Waitlist {
+			waitlist[number] = true
+		}
+		for number := range waitlist {
+			if findCartEntry(entries, number) == nil {
+				return nil, ErrNotInCart
+			}
+		}
+		for number := range opts.PermissionNumbers {
+			if findCartEntry(entries, number) == nil {
+				return nil, ErrNotInCart
+			}
+		}
+
+		for _, entry := range entries {
+			permission, ok := opts.PermissionNumbers[entry.Number]
+			if !ok && !waitlist[entry.Number] {
+				continue
+			}
+			if page, err = c.editCartEntry(page, entry, waitlist[entry.Number],
+				permission); err != nil {
+				return nil, err
+			}
+		}
+		if entries, err = parseCart(page.Selection); err != nil {
+			return nil, ess.AddCtx("uwquest: parsing shopping cart", err)
+		}
+	}
+
+	// Select the classes to enroll in.
+	form := make(url.Values)
+	if len(classNumbers) == 0 {
+		for _, entry := range entries {
+			classNumbers = append(classNumbers, entry.Number)
+		}
+	}
+	for _, number := range classNumbers {
+		entry := findCartEntry(entries, number)
+		if entry == nil {
+			return nil, ErrNotInCart
+		}
+		form.Set(fmt.Sprintf("P_SELECT$chk$%d", entry.Index), "Y")
+		form.Set(fmt.Sprintf("P_SELECT$%d", entry.Index), "Y")
+	}
+
+	return c.planEnrollment(ShoppingCartURL, page,
+		"DERIVED_REGFRM1_LINK_ADD_ENRL$82$", form, &EnrollmentPlan{
+			Action:    EnrollAction,
+			TermIndex: termIndex,
+			Classes:   classNumbers,
+		})
+}
+
+// editCartEntry opens the enrollment preferences of a shopping cart entry, and
+// sets them. It returns the shopping cart page that PeopleSoft returns to.
+func (c *Client) editCartEntry(page *gq.Document, entry *CartEntry,
+	waitlist bool, permissionNumber int) (*gq.Document, error) {
+	action := fmt.Sprintf("P_EDIT$%d", entry.Index)
+	if page.Find("#"+escapeID(action)).Length() == 0 {
+		return nil, fmt.Errorf("uwquest: could not locate edit button for "+
+			"class %d", entry.Number)
+	}
+	page, err := c.submitPage(ShoppingCartURL, page, action, nil)
+	if err != nil {
+		return nil, ess.AddCtx(fmt.Sprintf("uwquest: editing class %d",
+			entry.Number), err)
+	}
+	return c.setPreferences(ShoppingCartURL, page, waitlist, permissionNumber)
+}
+
+// PlanDrop plans dropping classes that are enrolled in for a particular term.
+func (c *Client) PlanDrop(termIndex int, classNumbers ...int) (
+	*EnrollmentPlan, error) {
+	if len(classNumbers) == 0 {
+		return nil, errors.New("uwquest: no classes to drop")
+	}
+
+	page, err := c.selectTerm(DropURL, termIndex)
+	if err != nil {
+		return nil, ess.AddCtx("uwquest: fetching drop classes page", err)
+	}
+
+	// Select the classes to drop.
+	rows := make(map[int]int)
+	page.Find(`[id^="E_CLASS_DESCR$"]`).Each(func(_ int, sel *gq.Selection) {
+		index, err := selectionIndex(sel)
+		if err != nil {
+			return
+		}
+		match := classNumberRegexp.FindStringSubmatch(sel.Text())
+		if match == nil {
+			return
+		}
+		number, _ := strconv.Atoi(match[1])
+		rows[number] = index
+	})
+
+	form := make(url.Values)
+	for _, number := range classNumbers {
+		index, ok := rows[number]
+		if !ok {
+			return nil, fmt.Errorf("uwquest: not enrolled in class %d", number)
+		}
+		form.Set(fmt.Sprintf("DERIVED_REGFSLCT_SSR_SELECT$chk$%d", index), "Y")
+		form.Set(fmt.Sprintf("DERIVED_REGFSLCT_SSR_SELECT$%d", index), "Y")
+	}
+
+	return c.planEnrollment(DropURL, page, "DERIVED_REGFRM1_LINK_DROP_ENRL",
+		form, &EnrollmentPlan{
+			Action:    DropAction,
+			TermIndex: termIndex,
+			Classes:   classNumbers,
+		})
+}
+
+// PlanSwap plans swapping an enrolled class for another class during a
+// particular term.
+//
+// The options for the new class (i.e. its related components) are set using
+// opts, which may be nil.
+func (c *Client) PlanSwap(termIndex, from, to int, opts *CartOptions) (
+	*EnrollmentPlan, error) {
+	page, err := c.selectTerm(SwapURL, termIndex)
+	if err != nil {
+		return nil, ess.AddCtx("uwquest: fetching swap classes page", err)
+	}
+
+	form := make(url.Values)
+	form.Set("DERIVED_REGFRM1_DESCR50$225$", strconv.Itoa(from))
+	form.Set("DERIVED_REGFRM1_CLASS_NBR", strconv.Itoa(to))
+	page, err = c.addClass(SwapURL, page,
+		"DERIVED_REGFRM1_SSR_PB_ADDTOLIST1$184$", form, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &EnrollmentPlan{
+		Action:    SwapAction,
+		TermIndex: termIndex,
+		Classes:   []int{from, to},
+		url:       SwapURL,
+		page:      page,
+	}
+	plan.Messages, err = parseEnrollmentMessages(page.Selection)
+	return plan, ess.AddCtx("uwquest: parsing confirmation page", err)
+}
+
+// planEnrollment submits an enrollment page using action, and fills in plan
+// with the resulting confirmation page.
+func (c *Client) planEnrollment(pageURL string, page *gq.Document,
+	action string, form url.Values, plan *EnrollmentPlan) (*EnrollmentPlan,
+	error) {
+	page, err := c.submitPage(pageURL, page, action, form)
+	if err != nil {
+		return nil, ess.AddCtx("uwquest: proceeding to confirmation page", err)
+	}
+	if msg := pageError(page.Selection); msg != "" {
+		return nil, fmt.Errorf("uwquest: proceeding to confirmation page: %s",
+			msg)
+	}
+
+	plan.url, plan.page = pageURL, page
+	plan.Messages, err = parseEnrollmentMessages(page.Selection)
+	return plan, ess.AddCtx("uwquest: parsing confirmation page", err)
+}
+
+// Apply submits an EnrollmentPlan to Quest, and returns the results for each
+// class.
+//
+// A plan can only be applied once, even if applying it fails, since the state
+// of the enrollment is unknown afterwards. This holds even if Apply is called
+// with the same plan from several goroutines at once.
+func (c *Client) Apply(plan *EnrollmentPlan) (results []*EnrollmentResult,
+	err error) {
+	if plan.page == nil {
+		return nil, ErrPlanIncomplete
+	}
+	plan.mu.Lock()
+	applied := plan.applied
+	plan.applied = true
+	plan.mu.Unlock()
+	if applied {
+		return nil, ErrPlanApplied
+	}
+	defer func() {
+		c.Audit.record(plan.Action.String(), fmt.Sprintf("term %d, classes %v",
+			plan.TermIndex, plan.Classes), err)
+	}()
+
+	page, err := c.submitPage(plan.url, plan.page,
+		"DERIVED_REGFRM1_SSR_PB_SUBMIT", nil)
+	if err != nil {
+		return nil, ess.AddCtx("uwquest: submitting enrollment", err)
+	}
+	if msg := pageError(page.Selection); msg != "" {
+		return nil, fmt.Errorf("uwquest: submitting enrollment: %s", msg)
+	}
+
+	results, err = parseEnrollmentResults(page.Selection)
+	return results, ess.AddCtx("uwquest: parsing enrollment results", err)
+}
+
+// classNumberRegexp matches class numbers in class descriptions, i.e. the
+// 5123 in "CS 135-001 (5123)".
+var classNumberRegexp = regexp.MustCompile(`\((\d+)\)`)
+
+// parseEnrollmentMessages parses the classes listed on an enrollment
+// confirmation page, along with their messages.
+func parseEnrollmentMessages(sel *gq.Selection) ([]*EnrollmentMessage,
+	error) {
+	var (
+		messages []*EnrollmentMessage
+		err      error
+	)
+	sel.Find(`[id^="R_CLASS_NAME$"]`).EachWithBreak(
+		func(_ int, name *gq.Selection) bool {
+			var index int
+			if index, err = selectionIndex(name); err != nil {
+				return false
+			}
+
+			msg := &EnrollmentMessage{
+				Class: strings.Join(textLines(name), " "),
+			}
+			scraper := indexedScraper{Index: index, Sel: sel}
+			if cell, err := scraper.Find("DERIVED_REGFRM1_SS_MESSAGE_LONG",
+				"message"); err == nil {
+				msg.Message = strings.Join(textLines(cell), " ")
+			}
+			messages = append(messages, msg)
+			return true
+		})
+	if err == nil && len(messages) == 0 {
+		err = errors.New("could not find any classes")
+	}
+	return messages, err
+}
+
+// parseEnrollmentResults parses the results table of an enrollment results
+// page.
+func parseEnrollmentResults(sel *gq.Selection) ([]*EnrollmentResult, error) {
+	sel = sel.Find(`#SSR_SS_ERD_ER\$scroll\$0`)
+	if sel.Length() != 1 {
+		return nil, errors.New("could not locate results table")
+	}
+
+	var (
+		results []*EnrollmentResult
+		err     error
+	)
+	sel.Find(`[id^="R_CLASS_NAME$"]`).EachWithBreak(
+		func(i int, name *gq.Selection) bool {
+			var index int
+			if index, err = selectionIndex(name); err != nil {
+				return false
+			}
+
+			var (
+				result  = &EnrollmentResult{Class: strings.TrimSpace(name.Text())}
+				scraper = indexedScraper{Index: index, Sel: sel}
+				cell    *gq.Selection
+			)
+			cell, err = scraper.Find("DERIVED_REGFRM1_SS_MESSAGE_LONG", "message")
+			if err != nil {
+				ess.AddCtxTo(fmt.Sprintf("row %d", i), &err)
+				return false
+			}
+			result.Message = strings.Join(textLines(cell), " ")
+
+			cell, err = scraper.Find("win0divDERIVED_REGFRM1_SSR_STATUS_LONG",
+				"status")
+			if err != nil {
+				ess.AddCtxTo(fmt.Sprintf("row %d", i), &err)
+				return false
+			}
+			alt, _ := cell.Find("img").Attr("alt")
+			result.Success = strings.TrimSpace(alt) == "Success"
+
+			results = append(results, result)
+			return true
+		})
+	return results, err
+}
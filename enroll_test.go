@@ -0,0 +1,14 @@
+package uwquest_test
+
+import (
+	"testing"
+
+	"github.com/stevenxie/uwquest"
+)
+
+func TestClient_Apply_Incomplete(t *testing.T) {
+	if _, err := client.Apply(new(uwquest.EnrollmentPlan)); err !=
+		uwquest.ErrPlanIncomplete {
+		t.Errorf("Expected ErrPlanIncomplete, got: %v", err)
+	}
+}
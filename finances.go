@@ -0,0 +1,264 @@
+package uwquest
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	gq "github.com/PuerkitoBio/goquery"
+	ess "github.com/unixpickle/essentials"
+)
+
+// An AccountSummary summarizes a student's account balance.
+type AccountSummary struct {
+	Balance   Money
+	AmountDue Money // the amount that is due now
+	FutureDue Money // the amount that is due in the future
+	Due       []*PaymentDue
+}
+
+func (as *AccountSummary) String() string {
+	return fmt.Sprintf("AccountSummary{Balance: %s, AmountDue: %s, "+
+		"FutureDue: %s, Due: %v}", as.Balance, as.AmountDue, as.FutureDue, as.Due)
+}
+
+// PaymentDue is an amount that is due to be paid by a particular date.
+type PaymentDue struct {
+	Date   time.Time
+	Term   string
+	Amount Money
+}
+
+func (pd *PaymentDue) String() string {
+	return fmt.Sprintf("PaymentDue{Date: %s, Term: %s, Amount: %s}",
+		pd.Date.Format("2006-01-02"), pd.Term, pd.Amount)
+}
+
+// ActivityKind is the kind of an account activity item.
+type ActivityKind int
+
+// The kinds of account activity.
+const (
+	Charge ActivityKind = iota
+	Payment
+	Credit
+)
+
+func (k ActivityKind) String() string {
+	switch k {
+	case Charge:
+		return "Charge"
+	case Payment:
+		return "Payment"
+	case Credit:
+		return "Credit"
+	default:
+		return fmt.Sprintf("ActivityKind(%d)", int(k))
+	}
+}
+
+// An ActivityItem is an itemized charge, payment, or credit on a student's
+// account.
+type ActivityItem struct {
+	Index       int
+	Date        time.Time
+	Term        string
+	Description string
+	Kind        ActivityKind
+
+	// Amount is the change in the account balance caused by the item; it is
+	// negative for payments and credits.
+	Amount Money
+}
+
+func (ai *ActivityItem) String() string {
+	return fmt.Sprintf("ActivityItem{Index: %d, Date: %s, Term: %s, "+
+		"Description: %s, Kind: %s, Amount: %s}", ai.Index,
+		ai.Date.Format("2006-01-02"), ai.Term, ai.Description, ai.Kind, ai.Amount)
+}
+
+// AccountSummary fetches a summary of the student's account balance.
+func (c *Client) AccountSummary() (*AccountSummary, error) {
+	page, err := c.fetchPage(AccountSummaryURL)
+	if err != nil {
+		return nil, ess.AddCtx("uwquest: fetching account summary page", err)
+	}
+
+	var (
+		summary = new(AccountSummary)
+		fields  = []struct {
+			ID, Desc string
+			Dest     *Money
+		}{
+			{"SSF_SS_ACCT_SUM_TOTAL_BAL", "balance", &summary.Balance},
+			{"SSF_SS_ACCT_SUM_DUE_NOW", "amount due", &summary.AmountDue},
+			{"SSF_SS_ACCT_SUM_FUTURE_DUE", "future amount due",
+				&summary.FutureDue},
+		}
+	)
+	for _, field := range fields {
+		if page.Find("#"+escapeID(field.ID)).Length() == 0 {
+			return nil, fmt.Errorf("uwquest: could not locate %s", field.Desc)
+		}
+		m, err := parseOptionalMoney(fieldText(page.Selection, field.ID))
+		if err != nil {
+			return nil, ess.AddCtx(fmt.Sprintf("uwquest: parsing %s", field.Desc),
+				err)
+		}
+		if m != nil {
+			*field.Dest = *m
+		}
+	}
+
+	// Parse due dates, if there are any.
+	var due *PaymentDue
+	page.Find(`[id^="SSF_SS_DUE_VW_DUE_DT$"]`).EachWithBreak(
+		func(i int, date *gq.Selection) bool {
+			var index int
+			if index, err = selectionIndex(date); err != nil {
+				return false
+			}
+			if due, err = parsePaymentDueRow(page.Selection, index); err != nil {
+				ess.AddCtxTo(fmt.Sprintf("row %d", i), &err)
+				return false
+			}
+			summary.Due = append(summary.Due, due)
+			return true
+		})
+	return summary, ess.AddCtx("uwquest: parsing due dates", err)
+}
+
+func parsePaymentDueRow(table *gq.Selection, index int) (*PaymentDue,
+	error) {
+	var (
+		due      = new(PaymentDue)
+		scraper  = indexedScraper{Index: index, Sel: table}
+		sel, err = scraper.Find("SSF_SS_DUE_VW_DUE_DT", "due date")
+	)
+	if err != nil {
+		return nil, err
+	}
+	if due.Date, err = parseQuestDate(sel.Text()); err != nil {
+		return nil, ess.AddCtx("parsing due date", err)
+	}
+
+	if sel, err = scraper.Find("SSF_SS_DUE_VW_TERM_DESCR", "term"); err != nil {
+		return nil, err
+	}
+	due.Term = strings.TrimSpace(sel.Text())
+
+	if sel, err = scraper.Find("SSF_SS_DUE_VW_DUE_AMT", "amount"); err != nil {
+		return nil, err
+	}
+	if due.Amount, err = ParseMoney(sel.Text()); err != nil {
+		return nil, ess.AddCtx("parsing amount", err)
+	}
+	return due, nil
+}
+
+// AccountActivity fetches the itemized charges, payments and credits on the
+// student's account for a particular term, where termIndex is the index of a
+// term returned by Terms.
+func (c *Client) AccountActivity(termIndex int) ([]*ActivityItem, error) {
+	terms, err := c.Terms()
+	if err != nil {
+		return nil, err
+	}
+	code, err := termCode(terms, termIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	page, err := c.fetchPage(AccountActivityURL)
+	if err != nil {
+		return nil, ess.AddCtx("uwquest: fetching account activity page", err)
+	}
+	form := make(url.Values)
+	form.Set("DERIVED_SSF_MSG_SSF_TERM", code)
+	page, err = c.submitPage(AccountActivityURL, page,
+		"DERIVED_SSF_MSG_SSF_REFRESH_PB", form)
+	if err != nil {
+		return nil, ess.AddCtx("uwquest: filtering account activity by term", err)
+	}
+
+	sel := page.Find(`#SSF_SS_ACT_VW\$scroll\$0`)
+	if sel.Length() != 1 {
+		return nil, errors.New("uwquest: could not locate account activity " +
+			"table")
+	}
+
+	var items []*ActivityItem
+	sel.Find(`[id^="SSF_SS_ACT_VW_DESCR$"]`).EachWithBreak(
+		func(i int, descr *gq.Selection) bool {
+			var index int
+			if index, err = selectionIndex(descr); err != nil {
+				return false
+			}
+
+			var item *ActivityItem
+			if item, err = parseActivityRow(sel, index); err != nil {
+				ess.AddCtxTo(fmt.Sprintf("row %d", i), &err)
+				return false
+			}
+			items = append(items, item)
+			return true
+		})
+	return items, ess.AddCtx("uwquest: parsing account activity", err)
+}
+
+func parseActivityRow(table *gq.Selection, index int) (*ActivityItem,
+	error) {
+	var (
+		item     = &ActivityItem{Index: index}
+		scraper  = indexedScraper{Index: index, Sel: table}
+		sel, err = scraper.Find("SSF_SS_ACT_VW_ITEM_EFFECTIVE_DT", "date")
+	)
+	if err != nil {
+		return nil, err
+	}
+	if item.Date, err = parseQuestDate(sel.Text()); err != nil {
+		return nil, ess.AddCtx("parsing date", err)
+	}
+
+	if sel, err = scraper.Find("SSF_SS_ACT_VW_TERM_DESCR", "term"); err != nil {
+		return nil, err
+	}
+	item.Term = strings.TrimSpace(sel.Text())
+
+	if sel, err = scraper.Find("SSF_SS_ACT_VW_DESCR", "description"); err != nil {
+		return nil, err
+	}
+	item.Description = strings.TrimSpace(sel.Text())
+
+	// Items have either a charge amount (which is negative for credits) or a
+	// payment amount.
+	if sel, err = scraper.Find("SSF_SS_ACT_VW_CHARGE_AMT", "charge"); err != nil {
+		return nil, err
+	}
+	charge, err := parseOptionalMoney(sel.Text())
+	if err != nil {
+		return nil, ess.AddCtx("parsing charge amount", err)
+	}
+	if sel, err = scraper.Find("SSF_SS_ACT_VW_PAYMENT_AMT",
+		"payment"); err != nil {
+		return nil, err
+	}
+	payment, err := parseOptionalMoney(sel.Text())
+	if err != nil {
+		return nil, ess.AddCtx("parsing payment amount", err)
+	}
+
+	switch {
+	case charge != nil && *charge < 0:
+		item.Kind, item.Amount = Credit, *charge
+	case charge != nil:
+		item.Kind, item.Amount = Charge, *charge
+	case payment != nil:
+		item.Kind, item.Amount = Payment, -*payment
+	default:
+		return nil, errors.New("item has no amount")
+	}
+	return item, nil
+}
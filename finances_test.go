@@ -0,0 +1,109 @@
+package uwquest_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stevenxie/uwquest"
+)
+
+func TestClient_AccountSummary(t *testing.T) {
+	summary, err := client.AccountSummary()
+	if err != nil {
+		t.Fatalf("Error while fetching account summary: %v", err)
+	}
+
+	t.Logf("Got account summary: %v", summary)
+}
+
+// staticPage responds to every request with the same HTML page.
+type staticPage string
+
+func (sp staticPage) RoundTrip(r *http.Request) (*http.Response, error) {
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"text/html"}},
+		Body:       ioutil.NopCloser(strings.NewReader(string(sp))),
+		Request:    r,
+	}, nil
+}
+
+func TestClient_AccountSummaryMissingFields(t *testing.T) {
+	c, err := uwquest.NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Session.Transport = staticPage("<html><body><form name='win0'>" +
+		"<p>An unexpected error occurred.</p></form></body></html>")
+
+	if summary, err := c.AccountSummary(); err == nil {
+		t.Errorf("Expected an error for a page without a summary, got: %v",
+			summary)
+	}
+}
+
+func TestParseMoney(t *testing.T) {
+	cases := []struct {
+		Text  string
+		Cents int64
+		Err   bool
+	}{
+		{Text: "$1,234.50", Cents: 123450},
+		{Text: "-12.00", Cents: -1200},
+		{Text: "-$12.00", Cents: -1200},
+		{Text: "$-12.00", Cents: -1200},
+		{Text: "(12.05)", Cents: -1205},
+		{Text: "($12.05)", Cents: -1205},
+		{Text: "0.5", Cents: 50},
+		{Text: ".5", Cents: 50},
+		{Text: "7", Cents: 700},
+		{Text: "\u00a0$3.00 ", Cents: 300},
+		{Text: "12.345", Err: true},
+		{Text: "", Err: true},
+		{Text: "$", Err: true},
+		{Text: "-$", Err: true},
+		{Text: ".", Err: true},
+		{Text: "--12.00", Err: true},
+		{Text: "-$-12.00", Err: true},
+		{Text: "$12.00-", Err: true},
+		{Text: "twelve", Err: true},
+	}
+	for _, c := range cases {
+		m, err := uwquest.ParseMoney(c.Text)
+		switch {
+		case c.Err && err == nil:
+			t.Errorf("Expected an error when parsing '%s', got %d cents", c.Text,
+				m.Cents())
+		case !c.Err && err != nil:
+			t.Errorf("Error parsing '%s': %v", c.Text, err)
+		case m.Cents() != c.Cents:
+			t.Errorf("Parsed '%s' incorrectly: got %d cents", c.Text, m.Cents())
+		}
+	}
+}
+
+func TestMoney_JSON(t *testing.T) {
+	m := uwquest.Money(-123450)
+	if s := m.String(); s != "-$1,234.50" {
+		t.Errorf("Formatted money incorrectly: got %s", s)
+	}
+
+	data, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("Error marshalling money: %v", err)
+	}
+	if string(data) != `"-1234.50"` {
+		t.Errorf("Marshalled money incorrectly: got %s", data)
+	}
+
+	var parsed uwquest.Money
+	if err = json.Unmarshal(data, &parsed); err != nil {
+		t.Fatalf("Error unmarshalling money: %v", err)
+	}
+	if parsed != m {
+		t.Errorf("Unmarshalled money incorrectly: got %s", parsed)
+	}
+}
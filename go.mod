@@ -1,8 +1,14 @@
 module github.com/stevenxie/uwquest
 
+go 1.24
+
 require (
 	github.com/PuerkitoBio/goquery v1.5.0
 	github.com/joho/godotenv v1.3.0
 	github.com/unixpickle/essentials v0.0.0-20180916162721-ae02bc395f1d
+)
+
+require (
+	github.com/andybalholm/cascadia v1.0.0 // indirect
 	golang.org/x/net v0.0.0-20181220203305-927f97764cc3 // indirect
 )
@@ -0,0 +1,133 @@
+package uwquest
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// GradeKind describes what kind of grade a Grade is.
+type GradeKind int
+
+// The kinds of grades that Quest reports.
+const (
+	NumericGrade         GradeKind = iota // a percentage, i.e. "71"
+	InProgressGrade                       // IP, or a blank grade
+	CreditGrade                           // CR
+	NoCreditGrade                         // NCR
+	IncompleteGrade                       // INC
+	DidNotWriteGrade                      // DNW
+	AegrotatGrade                         // AEG
+	WithdrawnGrade                        // WD
+	WithdrewFailingGrade                  // WF
+	NoMarkReportedGrade                   // NMR
+	AuditGrade                            // AUD
+	OtherGrade                            // any other symbolic grade
+)
+
+var gradeKindNames = [...]string{
+	NumericGrade:         "Numeric",
+	InProgressGrade:      "InProgress",
+	CreditGrade:          "Credit",
+	NoCreditGrade:        "NoCredit",
+	IncompleteGrade:      "Incomplete",
+	DidNotWriteGrade:     "DidNotWrite",
+	AegrotatGrade:        "Aegrotat",
+	WithdrawnGrade:       "Withdrawn",
+	WithdrewFailingGrade: "WithdrewFailing",
+	NoMarkReportedGrade:  "NoMarkReported",
+	AuditGrade:           "Audit",
+	OtherGrade:           "Other",
+}
+
+func (k GradeKind) String() string {
+	if k < 0 || int(k) >= len(gradeKindNames) {
+		return fmt.Sprintf("GradeKind(%d)", int(k))
+	}
+	return gradeKindNames[k]
+}
+
+// failingValue is the value that UW assigns to DNW and WF grades when
+// calculating averages.
+const failingValue = 32
+
+// passingValue is the minimum numeric grade required to pass a course.
+const passingValue = 50
+
+// symbolicGrades maps the symbolic grades that Quest reports to their
+// corresponding Grade.
+var symbolicGrades = map[string]Grade{
+	"IP":  {Kind: InProgressGrade},
+	"CR":  {Kind: CreditGrade, Passing: true},
+	"NCR": {Kind: NoCreditGrade},
+	"INC": {Kind: IncompleteGrade},
+	"DNW": {Kind: DidNotWriteGrade, Value: failingValue, InAverage: true},
+	"AEG": {Kind: AegrotatGrade, Passing: true},
+	"WD":  {Kind: WithdrawnGrade},
+	"WF":  {Kind: WithdrewFailingGrade, Value: failingValue, InAverage: true},
+	"NMR": {Kind: NoMarkReportedGrade},
+	"AUD": {Kind: AuditGrade},
+}
+
+// A Grade is the parsed form of a CourseGrade's Grade string.
+type Grade struct {
+	Kind GradeKind
+
+	// Value is the numeric value of the grade, which is only meaningful if
+	// InAverage is true.
+	Value float32
+
+	// InAverage is true if the grade counts towards term and cumulative
+	// averages.
+	InAverage bool
+
+	// Passing is true if the grade earns credit for the course.
+	Passing bool
+}
+
+func (g *Grade) String() string {
+	return fmt.Sprintf("Grade{Kind: %s, Value: %f, InAverage: %t, "+
+		"Passing: %t}", g.Kind, g.Value, g.InAverage, g.Passing)
+}
+
+// ParseGrade parses a grade string, as reported by Quest, into a Grade.
+//
+// A blank grade is treated as being in progress. Symbolic grades that are not
+// known are parsed as an OtherGrade, which (like CR and NCR) does not count
+// towards averages.
+func ParseGrade(s string) (*Grade, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if s == "" {
+		return &Grade{Kind: InProgressGrade}, nil
+	}
+	if grade, ok := symbolicGrades[s]; ok {
+		return &grade, nil
+	}
+
+	value, err := strconv.ParseFloat(s, 32)
+	if err == nil && (math.IsNaN(value) || math.IsInf(value, 0)) {
+		return nil, fmt.Errorf("uwquest: numeric grade '%s' is not finite", s)
+	}
+	if err != nil {
+		if strings.IndexFunc(s, unicode.IsDigit) == -1 {
+			return &Grade{Kind: OtherGrade}, nil
+		}
+		return nil, fmt.Errorf("uwquest: unknown grade '%s'", s)
+	}
+	if value < 0 || value > 100 {
+		return nil, fmt.Errorf("uwquest: numeric grade '%s' is out of range", s)
+	}
+	return &Grade{
+		Kind:      NumericGrade,
+		Value:     float32(value),
+		InAverage: true,
+		Passing:   value >= passingValue,
+	}, nil
+}
+
+// ParsedGrade parses cg.Grade into a Grade.
+func (cg *CourseGrade) ParsedGrade() (*Grade, error) {
+	return ParseGrade(cg.Grade)
+}
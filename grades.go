@@ -44,8 +44,70 @@ func (cg *CourseGrade) String() string {
 	return sb.String()
 }
 
-// Grades fetches the grades for a particular term.
-func (c *Client) Grades(termIndex int) ([]*CourseGrade, error) {
+// TermGrades represents the grades page for a particular term: the grades for
+// each course, along with the term and cumulative statistics. The statistics
+// are nil if Quest doesn't show any for the term (i.e. while it is in
+// progress).
+type TermGrades struct {
+	Courses    []*CourseGrade
+	Term       *Statistics
+	Cumulative *Statistics
+}
+
+func (tg *TermGrades) String() string {
+	return fmt.Sprintf("TermGrades{Courses: %v, Term: %v, Cumulative: %v}",
+		tg.Courses, tg.Term, tg.Cumulative)
+}
+
+// Statistics represents the unit and average totals that Quest shows for a
+// term, or cumulatively across all terms.
+//
+// Fields are nil when Quest leaves them blank.
+type Statistics struct {
+	UnitsTakenInAverage     *float32
+	UnitsPassedInAverage    *float32
+	UnitsTakenNotInAverage  *float32
+	UnitsPassedNotInAverage *float32
+	UnitsTaken              *float32
+	UnitsPassed             *float32
+	GradePoints             *float32
+	Average                 *float32
+}
+
+func (s *Statistics) String() string {
+	sb := new(strings.Builder)
+	sb.WriteString("Statistics{")
+	fields := []struct {
+		Name  string
+		Value *float32
+	}{
+		{"UnitsTakenInAverage", s.UnitsTakenInAverage},
+		{"UnitsPassedInAverage", s.UnitsPassedInAverage},
+		{"UnitsTakenNotInAverage", s.UnitsTakenNotInAverage},
+		{"UnitsPassedNotInAverage", s.UnitsPassedNotInAverage},
+		{"UnitsTaken", s.UnitsTaken},
+		{"UnitsPassed", s.UnitsPassed},
+		{"GradePoints", s.GradePoints},
+		{"Average", s.Average},
+	}
+	for i, field := range fields {
+		if i > 0 {
+			sb.WriteString(", ")
+		}
+		fmt.Fprintf(sb, "%s: ", field.Name)
+		if field.Value == nil {
+			sb.WriteString("<nil>")
+		} else {
+			fmt.Fprintf(sb, "%f", *field.Value)
+		}
+	}
+	sb.WriteByte('}')
+	return sb.String()
+}
+
+// Grades fetches the grades for a particular term, along with the term and
+// cumulative statistics shown on the grades page.
+func (c *Client) Grades(termIndex int) (*TermGrades, error) {
 	// Scrape hidden fields from Quest grades page.
 	res, err := c.Session.Get(GradesURL)
 	if err != nil {
@@ -103,7 +165,7 @@ func (c *Client) Grades(termIndex int) ([]*CourseGrade, error) {
 	}
 	sel = sel.Children()
 
-	var grades []*CourseGrade
+	tg := new(TermGrades)
 	sel.Children().EachWithBreak(func(i int, row *gq.Selection) bool {
 		if _, ok := row.Attr("id"); !ok {
 			return true // continue
@@ -115,15 +177,19 @@ func (c *Client) Grades(termIndex int) ([]*CourseGrade, error) {
 			return false
 		}
 
-		grades = append(grades, grade)
+		tg.Courses = append(tg.Courses, grade)
 		return true
 	})
 	if err != nil {
 		return nil, ess.AddCtx("uwquest: parsing grades table", err)
 	}
 
+	if tg.Term, tg.Cumulative, err = parseStatistics(doc.Selection); err != nil {
+		return nil, ess.AddCtx("uwquest: parsing statistics table", err)
+	}
+
 	err = res.Body.Close()
-	return grades, ess.AddCtx("uwquest: closing response body", err)
+	return tg, ess.AddCtx("uwquest: closing response body", err)
 }
 
 func parseGradeRow(row *gq.Selection) (*CourseGrade, error) {
@@ -193,3 +259,80 @@ func parseGradeRow(row *gq.Selection) (*CourseGrade, error) {
 
 	return cg, nil
 }
+
+// parseStatistics parses the statistics grid of the grades page, which
+// contains a row for the term totals followed by a row for the cumulative
+// totals. Quest omits the grid for terms without statistics (such as terms in
+// progress), in which case both are nil.
+func parseStatistics(sel *gq.Selection) (term, cumulative *Statistics,
+	err error) {
+	grid := sel.Find(`#STATS_ENRL\$scroll\$0`)
+	if grid.Length() == 0 {
+		return nil, nil, nil
+	}
+	sel = grid.Find("table.PSLEVEL1GRID")
+	if sel.Length() != 1 {
+		return nil, nil, errors.New("could not locate statistics table")
+	}
+
+	var stats []*Statistics
+	sel.Children().Children().EachWithBreak(func(i int, row *gq.Selection) bool {
+		if _, ok := row.Attr("id"); !ok {
+			return true // continue
+		}
+
+		var s *Statistics
+		if s, err = parseStatisticsRow(row, len(stats)); err != nil {
+			ess.AddCtxTo(fmt.Sprintf("row %d", i), &err)
+			return false
+		}
+
+		stats = append(stats, s)
+		return true
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(stats) != 2 {
+		return nil, nil, fmt.Errorf("expected 2 statistics rows, got %d",
+			len(stats))
+	}
+	return stats[0], stats[1], nil
+}
+
+func parseStatisticsRow(row *gq.Selection, index int) (*Statistics, error) {
+	var (
+		s       = new(Statistics)
+		scraper = indexedScraper{Index: index, Sel: row}
+		fields  = []struct {
+			ID, Desc string
+			Dest     **float32
+		}{
+			{"UW_DRVD_SSS_SCT_UNT_TAKEN_GPA", "units taken in average",
+				&s.UnitsTakenInAverage},
+			{"UW_DRVD_SSS_SCT_UNT_PASSD_GPA", "units passed in average",
+				&s.UnitsPassedInAverage},
+			{"UW_DRVD_SSS_SCT_UNT_TAKEN_NOGPA", "units taken not in average",
+				&s.UnitsTakenNotInAverage},
+			{"UW_DRVD_SSS_SCT_UNT_PASSD_NOGPA", "units passed not in average",
+				&s.UnitsPassedNotInAverage},
+			{"UW_DRVD_SSS_SCT_UNT_TAKEN_TOTAL", "total units taken",
+				&s.UnitsTaken},
+			{"UW_DRVD_SSS_SCT_UNT_PASSD_TOTAL", "total units passed",
+				&s.UnitsPassed},
+			{"UW_DRVD_SSS_SCT_GRADE_POINTS", "grade points", &s.GradePoints},
+			{"UW_DRVD_SSS_SCT_CUR_GPA", "average", &s.Average},
+		}
+	)
+	for _, field := range fields {
+		sel, err := scraper.Find(field.ID, field.Desc)
+		if err != nil {
+			return nil, err
+		}
+		if *field.Dest, err = parseOptionalFloat(sel.Text()); err != nil {
+			return nil, ess.AddCtx(fmt.Sprintf("parsing %s string into float",
+				field.Desc), err)
+		}
+	}
+	return s, nil
+}
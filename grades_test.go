@@ -7,12 +7,18 @@ import (
 func TestClient_Grades(t *testing.T) {
 	grades, err := client.Grades(0)
 	if err != nil {
-		t.Fatalf("Error fetching course grades")
+		t.Fatalf("Error fetching course grades: %v", err)
 	}
 
-	if n := len(grades); n == 0 {
+	if n := len(grades.Courses); n == 0 {
 		t.Fatal("Did not find any course grades for term 0.")
 	}
+	// Terms in progress have no statistics, but otherwise there are both term
+	// and cumulative statistics.
+	if (grades.Term == nil) != (grades.Cumulative == nil) {
+		t.Errorf("Expected both or neither of term and cumulative statistics "+
+			"for term 0, got: %v, %v", grades.Term, grades.Cumulative)
+	}
 
-	t.Logf("Got course grades for term 0: %v", grades)
+	t.Logf("Got grades for term 0: %v", grades)
 }
@@ -0,0 +1,151 @@
+package uwquest
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	gq "github.com/PuerkitoBio/goquery"
+	ess "github.com/unixpickle/essentials"
+)
+
+// CourseStatus is the status of a course in a student's course history.
+type CourseStatus int
+
+// The statuses of courses in a student's course history.
+const (
+	UnknownCourseStatus CourseStatus = iota
+	Taken
+	InProgress
+	Transferred
+)
+
+func (s CourseStatus) String() string {
+	switch s {
+	case UnknownCourseStatus:
+		return "Unknown"
+	case Taken:
+		return "Taken"
+	case InProgress:
+		return "InProgress"
+	case Transferred:
+		return "Transferred"
+	default:
+		return fmt.Sprintf("CourseStatus(%d)", int(s))
+	}
+}
+
+// parseCourseStatus parses the status icon of a course history row, using its
+// alt text.
+func parseCourseStatus(sel *gq.Selection) CourseStatus {
+	alt, _ := sel.Find("img").Attr("alt")
+	if alt == "" {
+		alt = sel.Text()
+	}
+	switch strings.TrimSpace(alt) {
+	case "Taken":
+		return Taken
+	case "In Progress":
+		return InProgress
+	case "Transferred":
+		return Transferred
+	default:
+		return UnknownCourseStatus
+	}
+}
+
+// A CourseRecord is a course in a student's course history.
+type CourseRecord struct {
+	Index       int
+	Name        string // i.e. "CS 135"
+	Description string
+	Term        string // i.e. "Fall 2017"; blank for some transfer credits
+	Grade       string // blank while the course is in progress
+	Units       *float32
+	Status      CourseStatus
+}
+
+func (cr *CourseRecord) String() string {
+	return fmt.Sprintf("CourseRecord{Index: %d, Name: %s, Description: %s, "+
+		"Term: %s, Grade: %s, Units: %s, Status: %s}", cr.Index, cr.Name,
+		cr.Description, cr.Term, cr.Grade, formatOptionalFloat(cr.Units),
+		cr.Status)
+}
+
+// CourseGrade converts cr to a CourseGrade, so that it can be used with
+// TermAverage and Project.
+func (cr *CourseRecord) CourseGrade() *CourseGrade {
+	return &CourseGrade{
+		Index:       cr.Index,
+		Name:        cr.Name,
+		Description: cr.Description,
+		Units:       cr.Units,
+		Grade:       cr.Grade,
+	}
+}
+
+// GradesByTerm groups the courses in a course history by term, and converts
+// them into CourseGrades. Courses without a term are grouped under the blank
+// term.
+func GradesByTerm(history []*CourseRecord) map[string][]*CourseGrade {
+	terms := make(map[string][]*CourseGrade)
+	for _, cr := range history {
+		terms[cr.Term] = append(terms[cr.Term], cr.CourseGrade())
+	}
+	return terms
+}
+
+// CourseHistory fetches every course that the student has taken, is taking,
+// or has received transfer credit for, across all terms.
+func (c *Client) CourseHistory() ([]*CourseRecord, error) {
+	page, err := c.fetchPage(CourseHistoryURL)
+	if err != nil {
+		return nil, ess.AddCtx("uwquest: fetching course history page", err)
+	}
+	if page, err = c.viewAll(CourseHistoryURL, page); err != nil {
+		return nil, ess.AddCtx("uwquest: expanding course history", err)
+	}
+
+	history, err := parseCourseHistory(page.Selection)
+	return history, ess.AddCtx("uwquest: parsing course history", err)
+}
+
+func parseCourseHistory(sel *gq.Selection) ([]*CourseRecord, error) {
+	sel = sel.Find(`#CRSE_HIST\$scroll\$0`)
+	if sel.Length() != 1 {
+		return nil, errors.New("could not locate course history table")
+	}
+
+	var (
+		history []*CourseRecord
+		err     error
+	)
+	sel.Find(`[id^="CRSE_NAME$"]`).EachWithBreak(
+		func(i int, name *gq.Selection) bool {
+			var index int
+			if index, err = selectionIndex(name); err != nil {
+				return false
+			}
+			id := func(field string) string {
+				return fmt.Sprintf("%s$%d", field, index)
+			}
+
+			cr := &CourseRecord{
+				Index:       index,
+				Name:        strings.TrimSpace(name.Text()),
+				Description: fieldText(sel, id("CRSE_DESCR")),
+				Term:        fieldText(sel, id("CRSE_TERM")),
+				Grade:       fieldText(sel, id("CRSE_GRADE")),
+				Status: parseCourseStatus(sel.Find("#" +
+					escapeID(id("CRSE_STATUS")))),
+			}
+			if cr.Units, err = parseOptionalFloat(fieldText(sel,
+				id("CRSE_UNITS"))); err != nil {
+				ess.AddCtxTo(fmt.Sprintf("row %d: parsing units", i), &err)
+				return false
+			}
+			history = append(history, cr)
+			return true
+		})
+	return history, err
+}
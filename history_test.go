@@ -0,0 +1,21 @@
+package uwquest_test
+
+import (
+	"testing"
+
+	"github.com/stevenxie/uwquest"
+)
+
+func TestClient_CourseHistory(t *testing.T) {
+	history, err := client.CourseHistory()
+	if err != nil {
+		t.Fatalf("Error while fetching course history: %v", err)
+	}
+	t.Logf("Got course history: %v", history)
+
+	for _, cr := range history {
+		if cr.Status == uwquest.UnknownCourseStatus {
+			t.Errorf("Course has an unknown status: %v", cr)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package uwquest
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	gq "github.com/PuerkitoBio/goquery"
+	ess "github.com/unixpickle/essentials"
+)
+
+// A Hold is a hold (service indicator) placed on a student's record, which
+// may block them from enrolling in classes or receiving transcripts.
+type Hold struct {
+	Index        int
+	Type         string
+	Reason       string
+	StartDate    time.Time // zero if Quest does not show a start date
+	Department   string
+	Amount       *Money // nil if there is no amount
+	Contact      string
+	Instructions string
+}
+
+func (h *Hold) String() string {
+	return fmt.Sprintf("Hold{Index: %d, Type: %s, Reason: %s, StartDate: %s, "+
+		"Department: %s, Amount: %v, Contact: %s, Instructions: %s}", h.Index,
+		h.Type, h.Reason, h.StartDate.Format("2006-01-02"), h.Department,
+		h.Amount, h.Contact, h.Instructions)
+}
+
+// Holds fetches the holds on the student's record from the Student Center.
+//
+// It returns nil if there are no holds.
+func (c *Client) Holds() ([]*Hold, error) {
+	list, err := c.followStudentCenterLink("DERIVED_SSS_SCL_SSS_MORE_HOLDS")
+	if err != nil {
+		return nil, ess.AddCtx("uwquest: fetching hold list", err)
+	}
+	if list == nil {
+		return nil, nil // no holds
+	}
+
+	var holds []*Hold
+	err = c.visitDetailPages(StudentCenterURL, list, "SRVC_IND_CD_TBL_DESCR",
+		"DERIVED_SSS_SRV_SSS_RETURN_PB",
+		func(index int, detail *gq.Document) error {
+			hold, err := parseHoldDetail(detail.Selection)
+			if err != nil {
+				return err
+			}
+			hold.Index = index
+			holds = append(holds, hold)
+			return nil
+		})
+	return holds, ess.AddCtx("uwquest: fetching hold details", err)
+}
+
+// parseHoldDetail parses a hold's detail page into a Hold.
+func parseHoldDetail(sel *gq.Selection) (*Hold, error) {
+	hold := &Hold{
+		Type:         fieldText(sel, "SRVC_IND_CD_TBL_DESCR"),
+		Reason:       fieldText(sel, "SRVC_IND_RSN_TBL_DESCR"),
+		Department:   fieldText(sel, "DERIVED_SSS_SRV_DEPT_DESCR"),
+		Contact:      fieldText(sel, "SRVC_IND_DATA_CONTACT"),
+		Instructions: fieldText(sel, "SRVC_IND_CD_TBL_DESCRLONG"),
+	}
+	if hold.Type == "" {
+		return nil, errors.New("could not find hold type")
+	}
+
+	var err error
+	if hold.Amount, err = parseOptionalMoney(fieldText(sel,
+		"SRVC_IND_DATA_AMOUNT")); err != nil {
+		return nil, ess.AddCtx("parsing amount", err)
+	}
+
+	if text := fieldText(sel, "SRVC_IND_DATA_SRVC_IND_ACT_DT"); text != "" {
+		date, err := parseQuestDate(text)
+		if err != nil {
+			return nil, ess.AddCtx("parsing start date", err)
+		}
+		hold.StartDate = date
+	}
+	return hold, nil
+}
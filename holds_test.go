@@ -0,0 +1,14 @@
+package uwquest_test
+
+import (
+	"testing"
+)
+
+func TestClient_Holds(t *testing.T) {
+	holds, err := client.Holds()
+	if err != nil {
+		t.Fatalf("Error while fetching holds: %v", err)
+	}
+
+	t.Logf("Got holds: %v", holds)
+}
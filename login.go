@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/cookiejar"
 	"net/url"
 	"strings"
 
@@ -15,6 +16,12 @@ import (
 // ErrBadLogin is an error which identifies a login error.
 var ErrBadLogin = errors.New("uwquest: bad login (invalid user ID or password)")
 
+// ErrSessionExpired is the error that a request fails with when Quest
+// redirects it to the login page, which it does once the Client's session has
+// expired. It is usually wrapped in the context of the request, so use
+// IsSessionExpired to check for it.
+var ErrSessionExpired = errors.New("uwquest: session expired")
+
 // Login authenticats the Client session with the Quest API backend.
 //
 // Requires a username (WatIAM ID) and password.
@@ -84,6 +91,65 @@ func (c *Client) Login(user, pass string) error {
 	return nil
 }
 
+// Logout ends the Client's Quest session, and clears its cookies. The Client
+// must be logged in again before it can fetch data from Quest.
+//
+// The cookies are cleared even if Quest cannot be reached, in which case the
+// session is left to expire on its own.
+func (c *Client) Logout() error {
+	const questLogoutURL = "https://quest.pecs.uwaterloo.ca/psp/SS/ACADEMIC/" +
+		"SA/?cmd=logout"
+
+	res, err := c.Session.Get(questLogoutURL)
+	if err == nil {
+		res.Body.Close()
+		if res.StatusCode != http.StatusOK {
+			err = fmt.Errorf("uwquest: got non-200 status code while logging "+
+				"out: got code %d", res.StatusCode)
+		}
+	} else {
+		err = ess.AddCtx("uwquest: logging out", err)
+	}
+
+	jar, jarErr := cookiejar.New(nil)
+	if jarErr != nil {
+		return ess.AddCtx("uwquest: creating cookiejar", jarErr)
+	}
+	c.Jar, c.Session.Jar = jar, jar
+	return err
+}
+
+// IsSessionExpired reports whether err was caused by the Client's session
+// having expired (see ErrSessionExpired), in which case the Client must log in
+// again.
+func IsSessionExpired(err error) bool {
+	for err != nil {
+		if err == ErrSessionExpired {
+			return true
+		}
+		if ce, ok := err.(*ess.CtxError); ok {
+			err = ce.Original
+		} else {
+			err = errors.Unwrap(err)
+		}
+	}
+	return false
+}
+
+// RetryExpired calls fn, and if it fails because the session expired, logs in
+// again as user and calls fn once more. Since fn is only called again if Quest
+// turned away its request, it may change data on Quest.
+func (c *Client) RetryExpired(user, pass string, fn func() error) error {
+	err := fn()
+	if !IsSessionExpired(err) {
+		return err
+	}
+	if err = c.Login(user, pass); err != nil {
+		return ess.AddCtx("uwquest: logging in again", err)
+	}
+	return fn()
+}
+
 // prelogin prepares c.Session for a login attempt by fetching pre-login cookies
 // and querying for the dynamic login link.
 func (c *Client) prelogin() (loginURL string, err error) {
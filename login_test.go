@@ -0,0 +1,65 @@
+package uwquest_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stevenxie/uwquest"
+)
+
+// loginRedirector redirects requests for Quest pages to the login page, as
+// Quest does once a session has expired.
+type loginRedirector struct{}
+
+func (loginRedirector) RoundTrip(r *http.Request) (*http.Response, error) {
+	res := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     make(http.Header),
+		Body:       ioutil.NopCloser(strings.NewReader("")),
+		Request:    r,
+	}
+	if r.URL.Query().Get("cmd") != "login" {
+		res.StatusCode = http.StatusFound
+		res.Header.Set("Location", "https://quest.pecs.uwaterloo.ca/psp/SS/"+
+			"ACADEMIC/SA/?cmd=login&languageCd=ENG")
+	}
+	return res, nil
+}
+
+func TestIsSessionExpired(t *testing.T) {
+	c, err := uwquest.NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Session.Transport = loginRedirector{}
+
+	if _, err := c.Terms(); !uwquest.IsSessionExpired(err) {
+		t.Errorf("Expected a session expired error, got: %v", err)
+	}
+	if uwquest.IsSessionExpired(errors.New("uwquest: parsing terms")) {
+		t.Error("Expected other errors not to be session expired errors.")
+	}
+}
+
+func TestClient_RetryExpired(t *testing.T) {
+	c, err := uwquest.NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.Session.Transport = loginRedirector{}
+
+	// Requests that fail for other reasons are not retried.
+	var calls int
+	failed := errors.New("uwquest: parsing terms")
+	err = c.RetryExpired("user", "pass", func() error {
+		calls++
+		return failed
+	})
+	if err != failed || calls != 1 {
+		t.Errorf("Expected 1 call failing with %v, got %d calls, and: %v",
+			failed, calls, err)
+	}
+}
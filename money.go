@@ -0,0 +1,128 @@
+package uwquest
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Money is an amount of money in cents, which avoids the rounding errors of
+// floating-point arithmetic.
+//
+// It is encoded in JSON as a decimal string, i.e. "1234.50".
+type Money int64
+
+// ParseMoney parses an amount of money shown on Quest, such as "$1,234.50",
+// "-$12.00", "$-12.00", or "(12.00)" (which is negative).
+func ParseMoney(s string) (Money, error) {
+	const nbsp = "\u00a0"
+	text := strings.TrimSpace(strings.Replace(s, nbsp, "", -1))
+
+	negative := false
+	if strings.HasPrefix(text, "(") && strings.HasSuffix(text, ")") {
+		negative, text = true, text[1:len(text)-1]
+	}
+
+	// The minus sign may come before or after the dollar sign.
+	minus := strings.HasPrefix(text, "-")
+	text = strings.TrimPrefix(strings.TrimPrefix(text, "-"), "$")
+	if !minus && strings.HasPrefix(text, "-") {
+		minus, text = true, text[1:]
+	}
+	if minus {
+		negative = !negative
+	}
+	text = strings.Replace(text, ",", "", -1)
+	if !strings.ContainsAny(text, "0123456789") {
+		return 0, fmt.Errorf("uwquest: invalid amount '%s'", s)
+	}
+
+	dollars, cents := text, "00"
+	if i := strings.IndexByte(text, '.'); i != -1 {
+		dollars, cents = text[:i], text[i+1:]
+		switch len(cents) {
+		case 1:
+			cents += "0"
+		case 2:
+		default:
+			return 0, fmt.Errorf("uwquest: invalid amount '%s'", s)
+		}
+	}
+	if dollars == "" {
+		dollars = "0"
+	}
+
+	d, err := strconv.ParseUint(dollars, 10, 63)
+	if err != nil {
+		return 0, fmt.Errorf("uwquest: invalid amount '%s'", s)
+	}
+	c, err := strconv.ParseUint(cents, 10, 8)
+	if err != nil {
+		return 0, fmt.Errorf("uwquest: invalid amount '%s'", s)
+	}
+
+	m := Money(d*100 + c)
+	if negative {
+		m = -m
+	}
+	return m, nil
+}
+
+// parseOptionalMoney parses text into Money, returning nil if text is blank.
+func parseOptionalMoney(text string) (*Money, error) {
+	const nbsp = "\u00a0"
+	if strings.TrimSpace(strings.Replace(text, nbsp, "", -1)) == "" {
+		return nil, nil
+	}
+	m, err := ParseMoney(text)
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+// Cents returns m as a number of cents.
+func (m Money) Cents() int64 { return int64(m) }
+
+// Decimal formats m as a decimal number, i.e. "-1234.50".
+func (m Money) Decimal() string {
+	sign, cents := "", int64(m)
+	if cents < 0 {
+		sign, cents = "-", -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// String formats m as a dollar amount, i.e. "-$1,234.50".
+func (m Money) String() string {
+	sign, cents := "", int64(m)
+	if cents < 0 {
+		sign, cents = "-", -cents
+	}
+
+	dollars := strconv.FormatInt(cents/100, 10)
+	for i := len(dollars) - 3; i > 0; i -= 3 {
+		dollars = dollars[:i] + "," + dollars[i:]
+	}
+	return fmt.Sprintf("%s$%s.%02d", sign, dollars, cents%100)
+}
+
+// MarshalJSON implements json.Marshaler.
+func (m Money) MarshalJSON() ([]byte, error) {
+	return json.Marshal(m.Decimal())
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (m *Money) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err != nil {
+		return err
+	}
+	parsed, err := ParseMoney(text)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
@@ -0,0 +1,52 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"text/template"
+
+	ess "github.com/unixpickle/essentials"
+)
+
+// An ExecNotifier delivers events by running a command, with the event
+// written to its standard input as JSON:
+//
+//	{"type": "seat_opened", "text": "...", "event": {...}}
+type ExecNotifier struct {
+	Command string
+	Args    []string
+
+	// Template renders the "text" of each event; it defaults to
+	// DefaultTemplate.
+	Template *template.Template
+
+	// Env is the command's environment, in the form "KEY=value". If nil, the
+	// command inherits the current process's environment.
+	Env []string
+}
+
+var _ Notifier = (*ExecNotifier)(nil)
+
+// Notify implements Notifier. The command is killed if ctx is done before it
+// exits.
+func (en *ExecNotifier) Notify(ctx context.Context, e Event) error {
+	input, err := eventJSON(en.Template, e)
+	if err != nil {
+		return ess.AddCtx("notify: encoding event", err)
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, en.Command, en.Args...)
+	cmd.Env = en.Env
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Stderr = &stderr
+	if err = cmd.Run(); err != nil {
+		if msg := bytes.TrimSpace(stderr.Bytes()); len(msg) > 0 {
+			err = fmt.Errorf("%v: %s", err, msg)
+		}
+		return ess.AddCtx(fmt.Sprintf("notify: running '%s'", en.Command), err)
+	}
+	return nil
+}
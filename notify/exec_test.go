@@ -0,0 +1,80 @@
+package notify_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"text/template"
+
+	"github.com/stevenxie/uwquest/notify"
+)
+
+func TestExecNotifier(t *testing.T) {
+	dir, err := ioutil.TempDir("", "notify")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	out := filepath.Join(dir, "event.json")
+	n := &notify.ExecNotifier{Command: "sh", Args: []string{"-c", "cat > " + out}}
+	if err = n.Notify(context.Background(),
+		&testEvent{Course: "CS 135"}); err != nil {
+		t.Fatalf("Error while running command: %v", err)
+	}
+
+	data, err := ioutil.ReadFile(out)
+	if err != nil {
+		t.Fatalf("Error while reading command output: %v", err)
+	}
+	if !strings.Contains(string(data), `"course":"CS 135"`) {
+		t.Errorf("Expected the event on stdin, got: %s", data)
+	}
+
+	n.Template = template.Must(template.New("").Parse("New: {{.Text}}"))
+	if err = n.Notify(context.Background(),
+		&testEvent{Course: "CS 135"}); err != nil {
+		t.Fatalf("Error while running command: %v", err)
+	}
+	if data, err = ioutil.ReadFile(out); err != nil {
+		t.Fatalf("Error while reading command output: %v", err)
+	}
+	if !strings.Contains(string(data), `"text":"New: CS 135 happened"`) {
+		t.Errorf("Expected the templated text on stdin, got: %s", data)
+	}
+
+	n = &notify.ExecNotifier{Command: "sh", Args: []string{"-c",
+		"echo oops >&2; exit 1"}}
+	if err = n.Notify(context.Background(), &testEvent{}); err == nil ||
+		!strings.Contains(err.Error(), "oops") {
+		t.Errorf("Expected an error including stderr, got: %v", err)
+	}
+}
+
+func TestRetry(t *testing.T) {
+	var calls int
+	n := &notify.Retry{
+		Notifier: notify.NotifierFunc(func(context.Context, notify.Event) error {
+			if calls++; calls < 3 {
+				return errors.New("failed")
+			}
+			return nil
+		}),
+		Attempts: 3,
+	}
+	if err := n.Notify(context.Background(), &testEvent{}); err != nil {
+		t.Errorf("Expected the third attempt to succeed, got: %v", err)
+	}
+
+	calls = -10
+	if err := n.Notify(context.Background(), &testEvent{}); err == nil {
+		t.Error("Expected an error after every attempt failed.")
+	}
+	if calls != -7 {
+		t.Errorf("Expected 3 attempts, got %d", calls+10)
+	}
+}
@@ -0,0 +1,54 @@
+package notify
+
+import (
+	"bytes"
+	"encoding/json"
+	"text/template"
+
+	ess "github.com/unixpickle/essentials"
+)
+
+// MessageData is the data that message templates are executed with.
+type MessageData struct {
+	Type  string // the event's type, i.e. "seat_opened"
+	Text  string // the event's description, i.e. its String()
+	Event Event
+}
+
+// DefaultTemplate is the message template used by notifiers that are not
+// configured with one; it renders the event's description.
+var DefaultTemplate = template.Must(template.New("message").Parse(
+	"{{.Text}}"))
+
+// Render renders a message for e using tmpl, or DefaultTemplate if tmpl is
+// nil.
+func Render(tmpl *template.Template, e Event) (string, error) {
+	if tmpl == nil {
+		tmpl = DefaultTemplate
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, &MessageData{
+		Type:  e.EventType(),
+		Text:  e.String(),
+		Event: e,
+	}); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+// eventJSON encodes e as a JSON object, along with its type and a message
+// rendered using tmpl (see Render):
+//
+//	{"type": "seat_opened", "text": "...", "event": {...}}
+func eventJSON(tmpl *template.Template, e Event) ([]byte, error) {
+	text, err := Render(tmpl, e)
+	if err != nil {
+		return nil, ess.AddCtx("rendering message", err)
+	}
+	return json.Marshal(struct {
+		Type  string `json:"type"`
+		Text  string `json:"text"`
+		Event Event  `json:"event"`
+	}{e.EventType(), text, e})
+}
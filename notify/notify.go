@@ -0,0 +1,30 @@
+// Package notify delivers events, such as a class seat opening up, to
+// students.
+package notify
+
+import (
+	"context"
+	"fmt"
+)
+
+// An Event is something that a student should be notified about.
+type Event interface {
+	// EventType is a short, stable identifier for the kind of event, i.e.
+	// "seat_opened".
+	EventType() string
+
+	fmt.Stringer
+}
+
+// A Notifier delivers events.
+type Notifier interface {
+	Notify(ctx context.Context, e Event) error
+}
+
+// NotifierFunc is a function that implements Notifier.
+type NotifierFunc func(ctx context.Context, e Event) error
+
+// Notify calls f(ctx, e).
+func (f NotifierFunc) Notify(ctx context.Context, e Event) error {
+	return f(ctx, e)
+}
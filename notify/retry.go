@@ -0,0 +1,65 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Retry is a Notifier that retries failed notifications, doubling the delay
+// between each attempt.
+type Retry struct {
+	Notifier Notifier
+	Attempts int           // the total number of attempts; defaults to 3
+	Delay    time.Duration // the delay before the first retry
+}
+
+var _ Notifier = (*Retry)(nil)
+
+// Notify implements Notifier. It returns the last attempt's error if every
+// attempt fails.
+func (r *Retry) Notify(ctx context.Context, e Event) error {
+	attempts := r.Attempts
+	if attempts <= 0 {
+		attempts = 3
+	}
+
+	delay := r.Delay
+	for i := 1; ; i++ {
+		err := r.Notifier.Notify(ctx, e)
+		if err == nil || i == attempts {
+			return err
+		}
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+		delay *= 2
+	}
+}
+
+// Multi is a Notifier that delivers events to each of its Notifiers.
+type Multi []Notifier
+
+var _ Notifier = Multi(nil)
+
+// Notify implements Notifier. It notifies every Notifier, even if some fail,
+// and returns an error describing the failures.
+func (m Multi) Notify(ctx context.Context, e Event) error {
+	var msgs []string
+	for _, n := range m {
+		if err := n.Notify(ctx, e); err != nil {
+			msgs = append(msgs, err.Error())
+		}
+	}
+	if len(msgs) > 0 {
+		return fmt.Errorf("notify: %d of %d notifiers failed: %s", len(msgs),
+			len(m), strings.Join(msgs, "; "))
+	}
+	return nil
+}
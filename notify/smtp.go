@@ -0,0 +1,143 @@
+package notify
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"text/template"
+	"time"
+
+	ess "github.com/unixpickle/essentials"
+)
+
+// DefaultSubjectTemplate is the subject template used by an SMTPNotifier that
+// is not configured with one.
+var DefaultSubjectTemplate = template.Must(template.New("subject").Parse(
+	"[uwquest] {{.Type}}"))
+
+// An SMTPNotifier delivers events by email.
+type SMTPNotifier struct {
+	Addr string // the SMTP server's address, i.e. "smtp.gmail.com:587"
+
+	// Username and Password are used to authenticate with the server, if
+	// Username is set. Quest credentials should never be reused here.
+	Username, Password string
+
+	From string
+	To   []string
+
+	// Subject and Body are the templates for each email; they default to
+	// DefaultSubjectTemplate and DefaultTemplate.
+	Subject, Body *template.Template
+
+	// TLSConfig is used for STARTTLS, which is used whenever the server
+	// supports it. If InsecureNoTLS is false, emails are not sent to servers
+	// that don't support STARTTLS.
+	TLSConfig     *tls.Config
+	InsecureNoTLS bool
+}
+
+var _ Notifier = (*SMTPNotifier)(nil)
+
+// Notify implements Notifier.
+func (sn *SMTPNotifier) Notify(ctx context.Context, e Event) error {
+	if len(sn.To) == 0 {
+		return errors.New("notify: no email recipients")
+	}
+	subject, err := Render(orDefault(sn.Subject, DefaultSubjectTemplate), e)
+	if err != nil {
+		return ess.AddCtx("notify: rendering email subject", err)
+	}
+	body, err := Render(sn.Body, e)
+	if err != nil {
+		return ess.AddCtx("notify: rendering email body", err)
+	}
+
+	host, _, err := net.SplitHostPort(sn.Addr)
+	if err != nil {
+		return ess.AddCtx("notify: parsing SMTP address", err)
+	}
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", sn.Addr)
+	if err != nil {
+		return ess.AddCtx("notify: connecting to SMTP server", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	} else {
+		conn.SetDeadline(time.Now().Add(time.Minute))
+	}
+
+	client, err := smtp.NewClient(conn, host)
+	if err != nil {
+		conn.Close()
+		return ess.AddCtx("notify: starting SMTP session", err)
+	}
+	defer client.Close()
+	return sn.send(client, host, subject, body)
+}
+
+func (sn *SMTPNotifier) send(client *smtp.Client, host, subject,
+	body string) error {
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		cfg := sn.TLSConfig
+		if cfg == nil {
+			cfg = &tls.Config{ServerName: host}
+		}
+		if err := client.StartTLS(cfg); err != nil {
+			return ess.AddCtx("notify: starting TLS", err)
+		}
+	} else if !sn.InsecureNoTLS {
+		return errors.New("notify: SMTP server does not support STARTTLS")
+	}
+
+	if sn.Username != "" {
+		auth := smtp.PlainAuth("", sn.Username, sn.Password, host)
+		if err := client.Auth(auth); err != nil {
+			return ess.AddCtx("notify: authenticating with SMTP server", err)
+		}
+	}
+
+	if err := client.Mail(sn.From); err != nil {
+		return ess.AddCtx("notify: setting email sender", err)
+	}
+	for _, to := range sn.To {
+		if err := client.Rcpt(to); err != nil {
+			return ess.AddCtx(fmt.Sprintf("notify: adding recipient '%s'", to), err)
+		}
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return ess.AddCtx("notify: starting email data", err)
+	}
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\n"+
+		"Content-Type: text/plain; charset=utf-8\r\n\r\n%s\r\n", sn.From,
+		strings.Join(sn.To, ", "), headerValue(subject),
+		strings.Replace(body, "\n", "\r\n", -1))
+	if _, err = w.Write([]byte(msg)); err != nil {
+		w.Close()
+		return ess.AddCtx("notify: writing email", err)
+	}
+	if err = w.Close(); err != nil {
+		return ess.AddCtx("notify: sending email", err)
+	}
+	return ess.AddCtx("notify: ending SMTP session", client.Quit())
+}
+
+// headerValue makes s safe to use as an email header value, by joining its
+// lines.
+func headerValue(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
+
+func orDefault(tmpl, def *template.Template) *template.Template {
+	if tmpl == nil {
+		return def
+	}
+	return tmpl
+}
@@ -0,0 +1,139 @@
+package notify_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+
+	"github.com/stevenxie/uwquest/notify"
+)
+
+// testEvent is a notify.Event for tests.
+type testEvent struct {
+	Course string `json:"course"`
+}
+
+func (te *testEvent) EventType() string { return "test_event" }
+func (te *testEvent) String() string    { return te.Course + " happened" }
+
+// smtpSession records what a fake SMTP server received.
+type smtpSession struct {
+	Auth string
+	From string
+	To   []string
+	Data string
+}
+
+// serveSMTP runs a minimal SMTP server (without STARTTLS) for a single
+// session, and sends what it received on the returned channel.
+func serveSMTP(t *testing.T) (string, <-chan *smtpSession) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Error while listening: %v", err)
+	}
+
+	sessions := make(chan *smtpSession, 1)
+	go func() {
+		defer ln.Close()
+		conn, err := ln.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		var (
+			tp      = textproto.NewConn(conn)
+			session = new(smtpSession)
+		)
+		defer func() { sessions <- session }()
+		tp.PrintfLine("220 localhost ready")
+		for {
+			line, err := tp.ReadLine()
+			if err != nil {
+				return
+			}
+			cmd := strings.ToUpper(strings.Fields(line)[0])
+			switch cmd {
+			case "EHLO", "HELO":
+				tp.PrintfLine("250-localhost")
+				tp.PrintfLine("250 AUTH PLAIN")
+			case "AUTH":
+				creds, _ := base64.StdEncoding.DecodeString(strings.Fields(line)[2])
+				session.Auth = string(creds)
+				tp.PrintfLine("235 authenticated")
+			case "MAIL":
+				session.From = line
+				tp.PrintfLine("250 ok")
+			case "RCPT":
+				session.To = append(session.To, line)
+				tp.PrintfLine("250 ok")
+			case "DATA":
+				tp.PrintfLine("354 go ahead")
+				data, _ := tp.ReadDotBytes()
+				session.Data = string(data)
+				tp.PrintfLine("250 ok")
+			case "QUIT":
+				tp.PrintfLine("221 bye")
+				return
+			default:
+				tp.PrintfLine("502 unknown command")
+			}
+		}
+	}()
+	return ln.Addr().String(), sessions
+}
+
+func TestSMTPNotifier(t *testing.T) {
+	addr, sessions := serveSMTP(t)
+	n := &notify.SMTPNotifier{
+		Addr:          addr,
+		Username:      "user",
+		Password:      "pass",
+		From:          "watcher@example.com",
+		To:            []string{"student@example.com"},
+		InsecureNoTLS: true,
+	}
+	if err := n.Notify(context.Background(),
+		&testEvent{Course: "CS 135"}); err != nil {
+		t.Fatalf("Error while sending email: %v", err)
+	}
+
+	session := <-sessions
+	if session.Auth != "\x00user\x00pass" {
+		t.Errorf("Unexpected credentials: %q", session.Auth)
+	}
+	if !strings.Contains(session.From, "watcher@example.com") ||
+		len(session.To) != 1 {
+		t.Errorf("Unexpected envelope: %v, %v", session.From, session.To)
+	}
+	msg, err := textproto.NewReader(bufio.NewReader(
+		strings.NewReader(session.Data))).ReadMIMEHeader()
+	if err != nil {
+		t.Fatalf("Error while parsing email headers: %v", err)
+	}
+	if subject := msg.Get("Subject"); subject != "[uwquest] test_event" {
+		t.Errorf("Unexpected subject: %q", subject)
+	}
+	if !strings.Contains(session.Data, "CS 135 happened") {
+		t.Errorf("Expected body to describe the event, got: %q", session.Data)
+	}
+}
+
+func TestSMTPNotifier_RequireTLS(t *testing.T) {
+	addr, sessions := serveSMTP(t)
+	n := &notify.SMTPNotifier{
+		Addr: addr,
+		From: "watcher@example.com",
+		To:   []string{"student@example.com"},
+	}
+	if err := n.Notify(context.Background(), &testEvent{}); err == nil {
+		t.Error("Expected an error from a server without STARTTLS.")
+	}
+	if session := <-sessions; session.Data != "" {
+		t.Error("Expected no email to be sent without TLS.")
+	}
+}
@@ -0,0 +1,133 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"text/template"
+
+	ess "github.com/unixpickle/essentials"
+)
+
+// SignatureHeader is the header that a WebhookNotifier puts the HMAC-SHA256
+// signature of each request body in, formatted as "sha256=<hex digest>".
+const SignatureHeader = "X-Uwquest-Signature"
+
+// A WebhookNotifier delivers events by POSTing them to a URL as JSON:
+//
+//	{"type": "seat_opened", "text": "...", "event": {...}}
+type WebhookNotifier struct {
+	URL string
+
+	// Template renders the "text" of each event; it defaults to
+	// DefaultTemplate.
+	Template *template.Template
+
+	// Secret, if set, is used to sign each request body (see
+	// SignatureHeader), so that the receiver can verify where it came from.
+	Secret []byte
+
+	Client *http.Client // defaults to http.DefaultClient
+}
+
+var _ Notifier = (*WebhookNotifier)(nil)
+
+// Notify implements Notifier.
+func (wn *WebhookNotifier) Notify(ctx context.Context, e Event) error {
+	body, err := eventJSON(wn.Template, e)
+	if err != nil {
+		return ess.AddCtx("notify: encoding event", err)
+	}
+
+	header := make(http.Header)
+	if wn.Secret != nil {
+		header.Set(SignatureHeader, "sha256="+Sign(wn.Secret, body))
+	}
+	return postJSON(ctx, wn.Client, wn.URL, header, body)
+}
+
+// Sign returns the hex-encoded HMAC-SHA256 of body using secret.
+func Sign(secret, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// A SlackNotifier delivers events to a Slack incoming webhook.
+type SlackNotifier struct {
+	URL      string
+	Template *template.Template // defaults to DefaultTemplate
+	Client   *http.Client       // defaults to http.DefaultClient
+}
+
+var _ Notifier = (*SlackNotifier)(nil)
+
+// Notify implements Notifier.
+func (sn *SlackNotifier) Notify(ctx context.Context, e Event) error {
+	return postChatMessage(ctx, sn.Client, sn.URL, sn.Template, "text", e)
+}
+
+// A DiscordNotifier delivers events to a Discord webhook.
+type DiscordNotifier struct {
+	URL      string
+	Template *template.Template // defaults to DefaultTemplate
+	Client   *http.Client       // defaults to http.DefaultClient
+}
+
+var _ Notifier = (*DiscordNotifier)(nil)
+
+// Notify implements Notifier.
+func (dn *DiscordNotifier) Notify(ctx context.Context, e Event) error {
+	return postChatMessage(ctx, dn.Client, dn.URL, dn.Template, "content", e)
+}
+
+// postChatMessage renders a message for e, and posts it to a chat webhook as
+// a JSON object with the message under key.
+func postChatMessage(ctx context.Context, client *http.Client, url string,
+	tmpl *template.Template, key string, e Event) error {
+	msg, err := Render(tmpl, e)
+	if err != nil {
+		return ess.AddCtx("notify: rendering message", err)
+	}
+	body, err := json.Marshal(map[string]string{key: msg})
+	if err != nil {
+		return ess.AddCtx("notify: encoding message", err)
+	}
+	return postJSON(ctx, client, url, nil, body)
+}
+
+// postJSON POSTs a JSON body to url, and checks that the response has a 2xx
+// status code.
+func postJSON(ctx context.Context, client *http.Client, url string,
+	header http.Header, body []byte) error {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
+	if err != nil {
+		return ess.AddCtx("notify: creating webhook request", err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := client.Do(req.WithContext(ctx))
+	if err != nil {
+		return ess.AddCtx("notify: performing webhook request", err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		msg, _ := ioutil.ReadAll(io.LimitReader(res.Body, 512))
+		return fmt.Errorf("notify: webhook responded with status code %d: %s",
+			res.StatusCode, bytes.TrimSpace(msg))
+	}
+	return nil
+}
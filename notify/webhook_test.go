@@ -0,0 +1,121 @@
+package notify_test
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"text/template"
+
+	"github.com/stevenxie/uwquest/notify"
+)
+
+// recordRequests starts a server that records the body and headers of each
+// request, and responds with status.
+func recordRequests(t *testing.T, status int) (*httptest.Server,
+	*[]*http.Request, *[][]byte) {
+	var (
+		reqs   []*http.Request
+		bodies [][]byte
+	)
+	srv := httptest.NewServer(http.HandlerFunc(
+		func(w http.ResponseWriter, r *http.Request) {
+			body, err := ioutil.ReadAll(r.Body)
+			if err != nil {
+				t.Errorf("Error while reading request body: %v", err)
+			}
+			reqs, bodies = append(reqs, r), append(bodies, body)
+			w.WriteHeader(status)
+		}))
+	return srv, &reqs, &bodies
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	srv, reqs, bodies := recordRequests(t, http.StatusNoContent)
+	defer srv.Close()
+
+	secret := []byte("secret")
+	n := &notify.WebhookNotifier{URL: srv.URL, Secret: secret}
+	if err := n.Notify(context.Background(),
+		&testEvent{Course: "CS 135"}); err != nil {
+		t.Fatalf("Error while calling webhook: %v", err)
+	}
+
+	body := (*bodies)[0]
+	var payload struct {
+		Type  string
+		Text  string
+		Event testEvent
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		t.Fatalf("Error while decoding payload: %v", err)
+	}
+	if payload.Type != "test_event" || payload.Text != "CS 135 happened" ||
+		payload.Event.Course != "CS 135" {
+		t.Errorf("Unexpected payload: %s", body)
+	}
+
+	sig := (*reqs)[0].Header.Get(notify.SignatureHeader)
+	if expected := "sha256=" + notify.Sign(secret, body); sig != expected {
+		t.Errorf("Expected signature %s, got %s", expected, sig)
+	}
+}
+
+func TestWebhookNotifier_Template(t *testing.T) {
+	srv, _, bodies := recordRequests(t, http.StatusOK)
+	defer srv.Close()
+
+	n := &notify.WebhookNotifier{
+		URL:      srv.URL,
+		Template: template.Must(template.New("").Parse("{{.Type}}: {{.Text}}")),
+	}
+	if err := n.Notify(context.Background(),
+		&testEvent{Course: "CS 135"}); err != nil {
+		t.Fatalf("Error while calling webhook: %v", err)
+	}
+
+	var payload struct{ Text string }
+	if err := json.Unmarshal((*bodies)[0], &payload); err != nil {
+		t.Fatalf("Error while decoding payload: %v", err)
+	}
+	if payload.Text != "test_event: CS 135 happened" {
+		t.Errorf("Expected templated text, got: %s", payload.Text)
+	}
+}
+
+func TestWebhookNotifier_ErrorStatus(t *testing.T) {
+	srv, _, _ := recordRequests(t, http.StatusInternalServerError)
+	defer srv.Close()
+
+	n := &notify.WebhookNotifier{URL: srv.URL}
+	if err := n.Notify(context.Background(), &testEvent{}); err == nil {
+		t.Error("Expected an error from a failed webhook.")
+	}
+}
+
+func TestChatNotifiers(t *testing.T) {
+	srv, _, bodies := recordRequests(t, http.StatusOK)
+	defer srv.Close()
+
+	tmpl := template.Must(template.New("").Parse("{{.Type}}: {{.Text}}"))
+	for _, n := range []notify.Notifier{
+		&notify.SlackNotifier{URL: srv.URL, Template: tmpl},
+		&notify.DiscordNotifier{URL: srv.URL, Template: tmpl},
+	} {
+		if err := n.Notify(context.Background(),
+			&testEvent{Course: "CS 135"}); err != nil {
+			t.Fatalf("Error while posting to chat webhook: %v", err)
+		}
+	}
+
+	for i, expected := range []string{
+		`{"text":"test_event: CS 135 happened"}`,
+		`{"content":"test_event: CS 135 happened"}`,
+	} {
+		if body := string((*bodies)[i]); body != expected {
+			t.Errorf("Expected payload %s, got %s", expected, body)
+		}
+	}
+}
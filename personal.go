@@ -0,0 +1,600 @@
+package uwquest
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	gq "github.com/PuerkitoBio/goquery"
+	ess "github.com/unixpickle/essentials"
+)
+
+// PersonalInfo is the personal information that a student has on file.
+type PersonalInfo struct {
+	Names             []*Name
+	Addresses         []*Address
+	Phones            []*Phone
+	Emails            []*Email
+	EmergencyContacts []*EmergencyContact
+}
+
+func (pi *PersonalInfo) String() string {
+	return fmt.Sprintf("PersonalInfo{Names: %v, Addresses: %v, Phones: %v, "+
+		"Emails: %v, EmergencyContacts: %v}", pi.Names, pi.Addresses, pi.Phones,
+		pi.Emails, pi.EmergencyContacts)
+}
+
+// A Name is one of a student's names.
+type Name struct {
+	Index int
+	Type  string // i.e. "Primary" (the legal name) or "Preferred"
+	Name  string
+}
+
+func (n *Name) String() string {
+	return fmt.Sprintf("Name{Index: %d, Type: %s, Name: %s}", n.Index, n.Type,
+		n.Name)
+}
+
+// An Address is one of a student's addresses.
+type Address struct {
+	Index int
+	Type  string // i.e. "Home" or "Mailing"
+	Lines []string
+}
+
+func (a *Address) String() string {
+	return fmt.Sprintf("Address{Index: %d, Type: %s, Lines: %q}", a.Index,
+		a.Type, a.Lines)
+}
+
+// A Phone is one of a student's phone numbers.
+type Phone struct {
+	Index     int
+	Type      string // i.e. "CELL" or "HOME"
+	Number    string
+	Extension string
+	Preferred bool
+}
+
+func (p *Phone) String() string {
+	return fmt.Sprintf("Phone{Index: %d, Type: %s, Number: %s, Extension: %s, "+
+		"Preferred: %t}", p.Index, p.Type, p.Number, p.Extension, p.Preferred)
+}
+
+// An Email is one of a student's email addresses.
+type Email struct {
+	Index     int
+	Type      string // i.e. "HOME" or "CAMP"
+	Address   string
+	Preferred bool
+}
+
+func (e *Email) String() string {
+	return fmt.Sprintf("Email{Index: %d, Type: %s, Address: %s, Preferred: %t}",
+		e.Index, e.Type, e.Address, e.Preferred)
+}
+
+// An EmergencyContact is someone to contact in case of a student's emergency.
+type EmergencyContact struct {
+	Index        int
+	Name         string
+	Relationship string
+	Phone        string
+	Primary      bool
+}
+
+func (ec *EmergencyContact) String() string {
+	return fmt.Sprintf("EmergencyContact{Index: %d, Name: %s, Relationship: %s, "+
+		"Phone: %s, Primary: %t}", ec.Index, ec.Name, ec.Relationship, ec.Phone,
+		ec.Primary)
+}
+
+// PersonalInfo fetches the student's personal information.
+func (c *Client) PersonalInfo() (*PersonalInfo, error) {
+	var (
+		info  = new(PersonalInfo)
+		pages = []struct {
+			URL, Desc string
+			Parse     func(*gq.Selection) error
+		}{
+			{NamesURL, "names", func(sel *gq.Selection) (err error) {
+				info.Names, err = parseNames(sel)
+				return err
+			}},
+			{AddressesURL, "addresses", func(sel *gq.Selection) (err error) {
+				info.Addresses, err = parseAddresses(sel)
+				return err
+			}},
+			{PhonesURL, "phone numbers", func(sel *gq.Selection) (err error) {
+				info.Phones, err = parsePhones(sel)
+				return err
+			}},
+			{EmailsURL, "email addresses", func(sel *gq.Selection) (err error) {
+				info.Emails, err = parseEmails(sel)
+				return err
+			}},
+			{EmergencyContactsURL, "emergency contacts",
+				func(sel *gq.Selection) (err error) {
+					info.EmergencyContacts, err = parseEmergencyContacts(sel)
+					return err
+				}},
+		}
+	)
+	for _, page := range pages {
+		doc, err := c.fetchPage(page.URL)
+		if err != nil {
+			return nil, ess.AddCtx(fmt.Sprintf("uwquest: fetching %s page",
+				page.Desc), err)
+		}
+		if err = page.Parse(doc.Selection); err != nil {
+			return nil, ess.AddCtx(fmt.Sprintf("uwquest: parsing %s", page.Desc),
+				err)
+		}
+	}
+	return info, nil
+}
+
+// rowIndices returns the row indices of the elements whose IDs start with
+// prefix followed by a '$'.
+func rowIndices(sel *gq.Selection, prefix string) []int {
+	var indices []int
+	sel.Find(fmt.Sprintf(`[id^="%s$"]`, prefix)).Each(
+		func(_ int, field *gq.Selection) {
+			if index, err := selectionIndex(field); err == nil {
+				indices = append(indices, index)
+			}
+		})
+	return indices
+}
+
+func parseNames(sel *gq.Selection) ([]*Name, error) {
+	var names []*Name
+	for _, i := range rowIndices(sel, "NAME_TYPE_TBL_DESCR") {
+		name := &Name{
+			Index: i,
+			Type:  fieldText(sel, fmt.Sprintf("NAME_TYPE_TBL_DESCR$%d", i)),
+			Name:  fieldText(sel, fmt.Sprintf("SCC_NAMES_VW_NAME_DISPLAY$%d", i)),
+		}
+		if name.Name == "" {
+			return nil, fmt.Errorf("could not find name in row %d", i)
+		}
+		names = append(names, name)
+	}
+	return names, nil
+}
+
+func parseAddresses(sel *gq.Selection) ([]*Address, error) {
+	var addrs []*Address
+	for _, i := range rowIndices(sel, "ADDR_TYPE_TBL_DESCR") {
+		id := fmt.Sprintf("DERIVED_ADDRESS_ADDRESSLONG$%d", i)
+		addr := &Address{
+			Index: i,
+			Type:  fieldText(sel, fmt.Sprintf("ADDR_TYPE_TBL_DESCR$%d", i)),
+			Lines: textLines(sel.Find("#" + escapeID(id))),
+		}
+		if len(addr.Lines) == 0 {
+			return nil, fmt.Errorf("could not find address in row %d", i)
+		}
+		addrs = append(addrs, addr)
+	}
+	return addrs, nil
+}
+
+func parsePhones(sel *gq.Selection) ([]*Phone, error) {
+	var phones []*Phone
+	for _, i := range rowIndices(sel, "PERSONAL_PHONE_PHONE") {
+		id := func(field string) string {
+			return fmt.Sprintf("PERSONAL_PHONE_%s$%d", field, i)
+		}
+		phone := &Phone{
+			Index:     i,
+			Type:      fieldValue(sel, id("PHONE_TYPE")),
+			Number:    fieldValue(sel, id("PHONE")),
+			Extension: fieldValue(sel, id("EXTENSION")),
+			Preferred: fieldValue(sel, id("PREF_PHONE_FLAG")) == "Y",
+		}
+		if phone.Number == "" {
+			return nil, fmt.Errorf("could not find phone number in row %d", i)
+		}
+		phones = append(phones, phone)
+	}
+	return phones, nil
+}
+
+func parseEmails(sel *gq.Selection) ([]*Email, error) {
+	var emails []*Email
+	for _, i := range rowIndices(sel, "EMAIL_ADDRESSES_EMAIL_ADDR") {
+		id := func(field string) string {
+			return fmt.Sprintf("EMAIL_ADDRESSES_%s$%d", field, i)
+		}
+		email := &Email{
+			Index:     i,
+			Type:      fieldValue(sel, id("E_ADDR_TYPE")),
+			Address:   fieldValue(sel, id("EMAIL_ADDR")),
+			Preferred: fieldValue(sel, id("PREF_EMAIL_FLAG")) == "Y",
+		}
+		if email.Address == "" {
+			return nil, fmt.Errorf("could not find email address in row %d", i)
+		}
+		emails = append(emails, email)
+	}
+	return emails, nil
+}
+
+func parseEmergencyContacts(sel *gq.Selection) ([]*EmergencyContact, error) {
+	var contacts []*EmergencyContact
+	for _, i := range rowIndices(sel, "EMERGENCY_CNTCT_CONTACT_NAME") {
+		id := func(field string) string {
+			return fmt.Sprintf("EMERGENCY_CNTCT_%s$%d", field, i)
+		}
+		contact := &EmergencyContact{
+			Index:        i,
+			Name:         fieldValue(sel, id("CONTACT_NAME")),
+			Relationship: fieldValue(sel, id("RELATIONSHIP")),
+			Phone:        fieldValue(sel, id("PHONE")),
+			Primary:      fieldValue(sel, id("PRIMARY_CONTACT")) == "Y",
+		}
+		if contact.Name == "" {
+			return nil, fmt.Errorf("could not find contact name in row %d", i)
+		}
+		contacts = append(contacts, contact)
+	}
+	return contacts, nil
+}
+
+// An InfoUpdate is a change to a student's personal information that has been
+// filled in on Quest, but not yet saved. Updates are saved using
+// Client.SaveInfoUpdate.
+//
+// Since PeopleSoft tracks the state of each session's pages, an update must be
+// saved before the Client makes any other requests to Quest.
+type InfoUpdate struct {
+	Kind    string // i.e. "Address"
+	Index   int    // the index of the record being updated
+	Changes []*FieldChange
+
+	url    string
+	page   *gq.Document
+	form   url.Values
+	fields []infoField
+
+	mu    sync.Mutex // guards saved
+	saved bool
+}
+
+func (iu *InfoUpdate) String() string {
+	return fmt.Sprintf("InfoUpdate{Kind: %s, Index: %d, Changes: %v}", iu.Kind,
+		iu.Index, iu.Changes)
+}
+
+// A FieldChange is a change to a single field of a student's personal
+// information.
+type FieldChange struct {
+	Field string
+	Old   string
+	New   string
+}
+
+func (fc *FieldChange) String() string {
+	return fmt.Sprintf("FieldChange{Field: %s, Old: %s, New: %s}", fc.Field,
+		fc.Old, fc.New)
+}
+
+// A ValidationError is returned by SaveInfoUpdate when Quest rejects the
+// values in an update.
+type ValidationError struct {
+	Fields []*FieldError
+}
+
+func (ve *ValidationError) Error() string {
+	msgs := make([]string, len(ve.Fields))
+	for i, fe := range ve.Fields {
+		msgs[i] = fe.Error()
+	}
+	return "uwquest: Quest rejected the update: " + strings.Join(msgs, "; ")
+}
+
+// A FieldError is a validation error shown by Quest. Field is blank if the
+// error does not belong to a particular field.
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (fe *FieldError) Error() string {
+	if fe.Field == "" {
+		return fe.Message
+	}
+	return fe.Field + ": " + fe.Message
+}
+
+// ErrUpdateSaved is returned by SaveInfoUpdate when an update has already been
+// saved.
+var ErrUpdateSaved = errors.New("uwquest: update was already saved")
+
+// A NameUpdate is a change to a student's preferred name. Blank fields are
+// left unchanged.
+type NameUpdate struct {
+	First, Middle, Last string
+}
+
+// An AddressUpdate is a change to an address. Blank fields are left
+// unchanged.
+type AddressUpdate struct {
+	Line1, Line2, Line3 string
+	City                string
+	Province            string // i.e. "ON"
+	PostalCode          string
+	Country             string // i.e. "CAN"
+}
+
+// A PhoneUpdate is a change to a phone number. Blank fields are left
+// unchanged.
+type PhoneUpdate struct {
+	Number    string
+	Extension string
+
+	// Preferred makes the phone number the preferred one. It cannot unset the
+	// preferred number, since Quest requires that there is always one.
+	Preferred bool
+}
+
+// An EmailUpdate is a change to an email address. Blank fields are left
+// unchanged.
+type EmailUpdate struct {
+	Address string
+
+	// Preferred makes the email address the preferred one. It cannot unset the
+	// preferred address, since Quest requires that there is always one.
+	Preferred bool
+}
+
+// An EmergencyContactUpdate is a change to an emergency contact. Blank fields
+// are left unchanged.
+type EmergencyContactUpdate struct {
+	Name         string
+	Relationship string // i.e. "PR" for a parent
+	Phone        string
+
+	// Primary makes the contact the primary one.
+	Primary bool
+}
+
+// infoField is a form field on a personal information page.
+type infoField struct {
+	Name, ID string
+	Value    string // the new value of the field, or blank to leave it as-is
+}
+
+// flagValue returns "Y" if flag is set, and blank otherwise.
+func flagValue(flag bool) string {
+	if flag {
+		return "Y"
+	}
+	return ""
+}
+
+// PlanNameUpdate fills in a change to the student's preferred name. Legal
+// names cannot be changed through Quest.
+func (c *Client) PlanNameUpdate(update *NameUpdate) (*InfoUpdate, error) {
+	page, err := c.fetchPage(NamesURL)
+	if err != nil {
+		return nil, ess.AddCtx("uwquest: fetching names page", err)
+	}
+	names, err := parseNames(page.Selection)
+	if err != nil {
+		return nil, ess.AddCtx("uwquest: parsing names", err)
+	}
+	index := -1
+	for _, name := range names {
+		if name.Type == "Preferred" {
+			index = name.Index
+			break
+		}
+	}
+	if index == -1 {
+		return nil, errors.New("uwquest: no preferred name on file")
+	}
+
+	return c.planInfoUpdate("Name", NamesURL, page,
+		fmt.Sprintf("DERIVED_CC_NAME_EDIT_PB$%d", index), index, []infoField{
+			{"First", "DERIVED_NAME_FIRST_NAME", update.First},
+			{"Middle", "DERIVED_NAME_MIDDLE_NAME", update.Middle},
+			{"Last", "DERIVED_NAME_LAST_NAME", update.Last},
+		})
+}
+
+// PlanAddressUpdate fills in a change to the address at index.
+func (c *Client) PlanAddressUpdate(index int, update *AddressUpdate) (
+	*InfoUpdate, error) {
+	page, err := c.fetchPage(AddressesURL)
+	if err != nil {
+		return nil, ess.AddCtx("uwquest: fetching addresses page", err)
+	}
+	return c.planInfoUpdate("Address", AddressesURL, page,
+		fmt.Sprintf("DERIVED_CC_ADDR_EDIT_PB$%d", index), index, []infoField{
+			{"Line1", "DERIVED_ADDRESS_ADDRESS1", update.Line1},
+			{"Line2", "DERIVED_ADDRESS_ADDRESS2", update.Line2},
+			{"Line3", "DERIVED_ADDRESS_ADDRESS3", update.Line3},
+			{"City", "DERIVED_ADDRESS_CITY", update.City},
+			{"Province", "DERIVED_ADDRESS_STATE", update.Province},
+			{"PostalCode", "DERIVED_ADDRESS_POSTAL", update.PostalCode},
+			{"Country", "DERIVED_ADDRESS_COUNTRY", update.Country},
+		})
+}
+
+// PlanPhoneUpdate fills in a change to the phone number at index.
+func (c *Client) PlanPhoneUpdate(index int, update *PhoneUpdate) (
+	*InfoUpdate, error) {
+	page, err := c.fetchPage(PhonesURL)
+	if err != nil {
+		return nil, ess.AddCtx("uwquest: fetching phone numbers page", err)
+	}
+	id := func(field string) string {
+		return fmt.Sprintf("PERSONAL_PHONE_%s$%d", field, index)
+	}
+	return c.planInfoUpdate("Phone", PhonesURL, page, "", index, []infoField{
+		{"Number", id("PHONE"), update.Number},
+		{"Extension", id("EXTENSION"), update.Extension},
+		{"Preferred", id("PREF_PHONE_FLAG"), flagValue(update.Preferred)},
+	})
+}
+
+// PlanEmailUpdate fills in a change to the email address at index.
+func (c *Client) PlanEmailUpdate(index int, update *EmailUpdate) (
+	*InfoUpdate, error) {
+	page, err := c.fetchPage(EmailsURL)
+	if err != nil {
+		return nil, ess.AddCtx("uwquest: fetching email addresses page", err)
+	}
+	id := func(field string) string {
+		return fmt.Sprintf("EMAIL_ADDRESSES_%s$%d", field, index)
+	}
+	return c.planInfoUpdate("Email", EmailsURL, page, "", index, []infoField{
+		{"Address", id("EMAIL_ADDR"), update.Address},
+		{"Preferred", id("PREF_EMAIL_FLAG"), flagValue(update.Preferred)},
+	})
+}
+
+// PlanEmergencyContactUpdate fills in a change to the emergency contact at
+// index.
+func (c *Client) PlanEmergencyContactUpdate(index int,
+	update *EmergencyContactUpdate) (*InfoUpdate, error) {
+	page, err := c.fetchPage(EmergencyContactsURL)
+	if err != nil {
+		return nil, ess.AddCtx("uwquest: fetching emergency contacts page", err)
+	}
+	return c.planInfoUpdate("EmergencyContact", EmergencyContactsURL, page,
+		fmt.Sprintf("DERIVED_CC_EMRG_EDIT_PB$%d", index), index, []infoField{
+			{"Name", "EMERGENCY_CNTCT_CONTACT_NAME", update.Name},
+			{"Relationship", "EMERGENCY_CNTCT_RELATIONSHIP", update.Relationship},
+			{"Phone", "EMERGENCY_CNTCT_PHONE", update.Phone},
+			{"Primary", "EMERGENCY_CNTCT_PRIMARY_CONTACT",
+				flagValue(update.Primary)},
+		})
+}
+
+// planInfoUpdate opens the edit page for a record using editAction (or edits
+// the record on page itself, if editAction is blank), and fills in the fields
+// that are changing. Each page is submitted to its own form's URL, falling
+// back to baseURL.
+func (c *Client) planInfoUpdate(kind, baseURL string, page *gq.Document,
+	editAction string, index int, fields []infoField) (*InfoUpdate, error) {
+	desc := strings.ToLower(kind)
+	if editAction != "" {
+		if page.Find("#"+escapeID(editAction)).Length() == 0 {
+			return nil, fmt.Errorf("uwquest: no %s at index %d", desc, index)
+		}
+		var err error
+		if page, err = c.submitPage(pageURL(page, baseURL), page, editAction,
+			nil); err != nil {
+			return nil, ess.AddCtx(fmt.Sprintf("uwquest: opening %s", desc), err)
+		}
+	}
+
+	update := &InfoUpdate{
+		Kind:   kind,
+		Index:  index,
+		url:    pageURL(page, baseURL),
+		page:   page,
+		form:   make(url.Values),
+		fields: fields,
+	}
+	for _, field := range fields {
+		sel := page.Find("#" + escapeID(field.ID))
+		if sel.Length() == 0 {
+			return nil, fmt.Errorf("uwquest: could not locate %s field '%s'",
+				desc, field.Name)
+		}
+		if field.Value == "" {
+			continue
+		}
+
+		old := fieldValue(page.Selection, field.ID)
+		if old == field.Value {
+			continue
+		}
+		update.Changes = append(update.Changes, &FieldChange{
+			Field: field.Name,
+			Old:   old,
+			New:   field.Value,
+		})
+		update.form.Set(field.ID, field.Value)
+		if typ, _ := sel.Attr("type"); typ == "checkbox" {
+			update.form.Set(checkboxName(field.ID), field.Value)
+		}
+	}
+	return update, nil
+}
+
+// checkboxName returns the name of the hidden field that PeopleSoft uses to
+// track the state of the checkbox with the given ID.
+func checkboxName(id string) string {
+	if i := strings.IndexByte(id, '$'); i != -1 {
+		return id[:i] + "$chk" + id[i:]
+	}
+	return id + "$chk"
+}
+
+// SaveInfoUpdate saves an InfoUpdate to Quest. If Quest rejects the new
+// values, it returns a *ValidationError.
+//
+// An update can only be saved once, even if saving it fails. This holds even
+// if SaveInfoUpdate is called with the same update from several goroutines at
+// once.
+func (c *Client) SaveInfoUpdate(update *InfoUpdate) (err error) {
+	update.mu.Lock()
+	saved := update.saved
+	update.saved = true
+	update.mu.Unlock()
+	if saved {
+		return ErrUpdateSaved
+	}
+	if len(update.Changes) == 0 {
+		return nil
+	}
+	defer func() {
+		c.Audit.record("Update"+update.Kind, fmt.Sprintf("index %d, changes %v",
+			update.Index, update.Changes), err)
+	}()
+
+	page, err := c.submitPage(update.url, update.page, "#ICSave", update.form)
+	if err != nil {
+		return ess.AddCtx("uwquest: saving update", err)
+	}
+	if verr := parseValidationError(page.Selection, update.fields); verr != nil {
+		return verr
+	}
+	return nil
+}
+
+// parseValidationError parses the validation errors shown on a page, which
+// PeopleSoft shows by highlighting the invalid fields alongside an alert
+// message. It returns nil if there are no errors.
+func parseValidationError(sel *gq.Selection,
+	fields []infoField) *ValidationError {
+	msg := strings.Join(textLines(sel.Find("#ALERTMSG")), " ")
+	if msg == "" {
+		msg = pageError(sel)
+	}
+
+	verr := new(ValidationError)
+	for _, field := range fields {
+		if sel.Find("#" + escapeID(field.ID)).HasClass("PSERROR") {
+			verr.Fields = append(verr.Fields, &FieldError{
+				Field:   field.Name,
+				Message: msg,
+			})
+		}
+	}
+	if len(verr.Fields) == 0 && msg != "" {
+		verr.Fields = append(verr.Fields, &FieldError{Message: msg})
+	}
+	if len(verr.Fields) == 0 {
+		return nil
+	}
+	return verr
+}
@@ -0,0 +1,59 @@
+package uwquest_test
+
+import (
+	"testing"
+
+	"github.com/stevenxie/uwquest"
+)
+
+func TestClient_PersonalInfo(t *testing.T) {
+	info, err := client.PersonalInfo()
+	if err != nil {
+		t.Fatalf("Error while fetching personal info: %v", err)
+	}
+	t.Logf("Got personal info: %v", info)
+
+	if len(info.Emails) == 0 {
+		t.Skip("No email addresses to plan an update for.")
+	}
+	update, err := client.PlanEmailUpdate(info.Emails[0].Index,
+		&uwquest.EmailUpdate{Address: info.Emails[0].Address})
+	if err != nil {
+		t.Fatalf("Error while planning email update: %v", err)
+	}
+	if len(update.Changes) != 0 {
+		t.Errorf("Expected no changes, got: %v", update.Changes)
+	}
+	if err = client.SaveInfoUpdate(update); err != nil {
+		t.Errorf("Error while saving empty update: %v", err)
+	}
+}
+
+func TestClient_SaveInfoUpdateOnce(t *testing.T) {
+	c, err := uwquest.NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Concurrent saves of the same update should only save it once.
+	var (
+		update = new(uwquest.InfoUpdate)
+		errs   = make(chan error)
+	)
+	for i := 0; i < 10; i++ {
+		go func() { errs <- c.SaveInfoUpdate(update) }()
+	}
+	var saves int
+	for i := 0; i < 10; i++ {
+		switch err := <-errs; err {
+		case nil:
+			saves++
+		case uwquest.ErrUpdateSaved:
+		default:
+			t.Errorf("Unexpected error: %v", err)
+		}
+	}
+	if saves != 1 {
+		t.Errorf("Expected 1 save, got %d", saves)
+	}
+}
@@ -0,0 +1,506 @@
+package uwquest
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	ess "github.com/unixpickle/essentials"
+)
+
+// Defaults for a SessionPool.
+const (
+	DefaultMaxConcurrent = 4
+	DefaultIdleTimeout   = 30 * time.Minute
+)
+
+// ErrUnknownUser is returned by a SessionPool for users that have not been
+// added to it.
+var ErrUnknownUser = errors.New("uwquest: unknown user")
+
+// A SessionStore persists the (encrypted) session cookies of the accounts in
+// a SessionPool, so that their sessions can be reused after a restart, rather
+// than logging in again.
+type SessionStore interface {
+	// LoadSession returns the data last saved for user, or nil if there is
+	// none.
+	LoadSession(user string) ([]byte, error)
+	SaveSession(user string, data []byte) error
+	DeleteSession(user string) error
+}
+
+// PoolOptions configures a SessionPool.
+type PoolOptions struct {
+	// MaxConcurrent is the maximum number of requests that the pool makes to
+	// Quest at once, across all accounts. It defaults to DefaultMaxConcurrent.
+	MaxConcurrent int
+
+	// IdleTimeout is the time after which an unused session is logged out by
+	// EvictIdle. It defaults to DefaultIdleTimeout.
+	IdleTimeout time.Duration
+
+	// Key is the AES key (16, 24, or 32 bytes long) used to encrypt passwords
+	// and session cookies. If it is nil, a random key is used; this is only
+	// allowed without a Store, since no other SessionPool could read the
+	// sessions saved with it.
+	Key []byte
+
+	// Store, if set, is where session cookies are saved. It requires a Key.
+	Store SessionStore
+
+	// Transport is what requests to Quest are made with. It defaults to
+	// http.DefaultTransport.
+	Transport http.RoundTripper
+}
+
+// A SessionPool manages logged-in Clients for many accounts.
+//
+// Each account gets its own Client (and cookie jar), and requests for the
+// same account are made one at a time, since PeopleSoft keeps the state of
+// each page in the session. Passwords are kept encrypted in memory, and are
+// only decrypted to log in.
+type SessionPool struct {
+	maxConcurrent int
+	idleTimeout   time.Duration
+	store         SessionStore
+	aead          cipher.AEAD
+	transport     http.RoundTripper
+
+	mu       sync.Mutex
+	accounts map[string]*account
+}
+
+// An account is a user in a SessionPool.
+type account struct {
+	mu       sync.Mutex // held while the account's Client is in use
+	user     string
+	pass     []byte // sealed
+	client   *Client
+	jar      *sessionJar // records the cookies of client
+	lastUsed time.Time
+}
+
+// NewSessionPool returns a new SessionPool. opts may be nil.
+func NewSessionPool(opts *PoolOptions) (*SessionPool, error) {
+	if opts == nil {
+		opts = new(PoolOptions)
+	}
+	pool := &SessionPool{
+		maxConcurrent: opts.MaxConcurrent,
+		idleTimeout:   opts.IdleTimeout,
+		store:         opts.Store,
+		accounts:      make(map[string]*account),
+	}
+	if pool.maxConcurrent <= 0 {
+		pool.maxConcurrent = DefaultMaxConcurrent
+	}
+	if pool.idleTimeout <= 0 {
+		pool.idleTimeout = DefaultIdleTimeout
+	}
+	base := opts.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	pool.transport = &limitedTransport{
+		Base: base,
+		Sem:  make(chan struct{}, pool.maxConcurrent),
+	}
+
+	key := opts.Key
+	if key == nil && opts.Store != nil {
+		return nil, errors.New("uwquest: a pool with a Store needs a Key, " +
+			"so that its saved sessions can be read after a restart")
+	}
+	if key == nil {
+		key = make([]byte, 32)
+		if _, err := io.ReadFull(rand.Reader, key); err != nil {
+			return nil, ess.AddCtx("uwquest: generating pool key", err)
+		}
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, ess.AddCtx("uwquest: creating pool cipher", err)
+	}
+	if pool.aead, err = cipher.NewGCM(block); err != nil {
+		return nil, ess.AddCtx("uwquest: creating pool cipher", err)
+	}
+	return pool, nil
+}
+
+// Add adds an account to the pool, replacing its password if it was already
+// added. It does not log in; that happens upon the account's first use.
+func (p *SessionPool) Add(user, pass string) error {
+	sealed, err := p.seal([]byte(pass))
+	if err != nil {
+		return ess.AddCtx("uwquest: encrypting password", err)
+	}
+
+	p.mu.Lock()
+	acct, ok := p.accounts[user]
+	if !ok {
+		acct = &account{user: user}
+		p.accounts[user] = acct
+	}
+	p.mu.Unlock()
+
+	acct.mu.Lock()
+	defer acct.mu.Unlock()
+	acct.pass = sealed
+	return nil
+}
+
+// Do calls fn with a logged-in Client for user, while no other requests are
+// being made for user.
+//
+// If fn fails because the session expired (see IsSessionExpired), and the
+// Client was not just logged in, Do logs in again and calls fn once more.
+// Since Quest turned away fn's request in that case, fn may change data on
+// Quest. fn must not keep the Client after it returns.
+func (p *SessionPool) Do(user string, fn func(c *Client) error) error {
+	p.mu.Lock()
+	acct, ok := p.accounts[user]
+	p.mu.Unlock()
+	if !ok {
+		return ErrUnknownUser
+	}
+
+	acct.mu.Lock()
+	defer acct.mu.Unlock()
+	defer func() { acct.lastUsed = time.Now() }()
+
+	var fresh bool
+	if acct.client == nil {
+		var err error
+		if fresh, err = p.connect(acct); err != nil {
+			return err
+		}
+	}
+	err := fn(acct.client)
+	if IsSessionExpired(err) && !fresh {
+		if err = p.login(acct); err != nil {
+			return ess.AddCtx("uwquest: logging in again", err)
+		}
+		err = fn(acct.client)
+	}
+	p.saveSession(acct)
+	return err
+}
+
+// connect creates a Client for acct, restoring its saved session if there is
+// one, or logging in otherwise. It reports whether it logged in.
+func (p *SessionPool) connect(acct *account) (loggedIn bool, err error) {
+	client, err := NewClient()
+	if err != nil {
+		return false, err
+	}
+	client.Session.Transport = p.transport
+	acct.jar = &sessionJar{CookieJar: client.Jar}
+	client.Session.Jar = acct.jar
+	acct.client = client
+
+	if p.store != nil {
+		data, err := p.store.LoadSession(acct.user)
+		if err != nil {
+			acct.client = nil
+			return false, ess.AddCtx("uwquest: loading saved session", err)
+		}
+		if data != nil {
+			if err := p.restoreSession(acct.jar, data); err == nil {
+				return false, nil
+			}
+		}
+	}
+	return true, p.login(acct)
+}
+
+func (p *SessionPool) login(acct *account) error {
+	pass, err := p.open(acct.pass)
+	if err != nil {
+		return ess.AddCtx("uwquest: decrypting password", err)
+	}
+	if err := acct.client.Login(acct.user, string(pass)); err != nil {
+		acct.client = nil
+		return err
+	}
+	return nil
+}
+
+// Remove logs user out, forgets their password, and deletes their saved
+// session.
+func (p *SessionPool) Remove(user string) error {
+	p.mu.Lock()
+	acct, ok := p.accounts[user]
+	delete(p.accounts, user)
+	p.mu.Unlock()
+	if !ok {
+		return ErrUnknownUser
+	}
+
+	acct.mu.Lock()
+	defer acct.mu.Unlock()
+	if acct.client != nil {
+		return p.logout(acct)
+	}
+	if p.store != nil {
+		return ess.AddCtx("uwquest: deleting saved session",
+			p.store.DeleteSession(user))
+	}
+	return nil
+}
+
+// EvictIdle logs out the sessions that have not been used for longer than the
+// pool's idle timeout. Their accounts stay in the pool, and are logged in
+// again when they are next used.
+func (p *SessionPool) EvictIdle() error {
+	var firstErr error
+	for _, acct := range p.accountList() {
+		acct.mu.Lock()
+		if acct.client != nil && time.Since(acct.lastUsed) > p.idleTimeout {
+			if err := p.logout(acct); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+		acct.mu.Unlock()
+	}
+	return firstErr
+}
+
+// Run calls EvictIdle periodically until ctx is done, and then returns ctx's
+// error. Errors from EvictIdle are passed to onError, if it is not nil.
+func (p *SessionPool) Run(ctx context.Context, onError func(error)) error {
+	ticker := time.NewTicker(p.idleTimeout / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+			if err := p.EvictIdle(); err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+// Close drops every session in the pool. If the pool has a Store, the
+// sessions are saved to it (and not logged out), so that they can be reused
+// after a restart; otherwise, they are logged out. The accounts stay in the
+// pool.
+func (p *SessionPool) Close() error {
+	var firstErr error
+	for _, acct := range p.accountList() {
+		acct.mu.Lock()
+		if p.store != nil {
+			p.saveSession(acct)
+			acct.client = nil
+		} else if err := p.logout(acct); err != nil && firstErr == nil {
+			firstErr = err
+		}
+		acct.mu.Unlock()
+	}
+	return firstErr
+}
+
+// accountList returns the accounts in the pool.
+func (p *SessionPool) accountList() []*account {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	accounts := make([]*account, 0, len(p.accounts))
+	for _, acct := range p.accounts {
+		accounts = append(accounts, acct)
+	}
+	return accounts
+}
+
+// logout logs acct's Client out, and drops it.
+func (p *SessionPool) logout(acct *account) error {
+	if acct.client == nil {
+		return nil
+	}
+	err := acct.client.Logout()
+	acct.client = nil
+	if p.store != nil {
+		if storeErr := p.store.DeleteSession(acct.user); storeErr != nil &&
+			err == nil {
+			err = storeErr
+		}
+	}
+	return ess.AddCtx(fmt.Sprintf("uwquest: logging out %s", acct.user), err)
+}
+
+// saveSession saves the cookies of acct's Client to p.store, if it is set.
+// Failing to save a session is not an error, since the account can always
+// log in again.
+func (p *SessionPool) saveSession(acct *account) {
+	if p.store == nil || acct.client == nil {
+		return
+	}
+	data, err := json.Marshal(acct.jar.saved())
+	if err != nil {
+		return
+	}
+	if data, err = p.seal(data); err != nil {
+		return
+	}
+	p.store.SaveSession(acct.user, data)
+}
+
+// restoreSession sets the cookies in jar from a session saved by saveSession.
+func (p *SessionPool) restoreSession(jar *sessionJar, data []byte) error {
+	data, err := p.open(data)
+	if err != nil {
+		return err
+	}
+	var cookies []*savedCookie
+	if err := json.Unmarshal(data, &cookies); err != nil {
+		return err
+	}
+	for _, c := range cookies {
+		u, err := url.Parse(c.URL)
+		if err != nil {
+			return err
+		}
+		jar.SetCookies(u, []*http.Cookie{{
+			Name:     c.Name,
+			Value:    c.Value,
+			Path:     c.Path,
+			Domain:   c.Domain,
+			Expires:  c.Expires,
+			Secure:   c.Secure,
+			HttpOnly: c.HttpOnly,
+		}})
+	}
+	return nil
+}
+
+// savedCookie is a cookie in a saved session, along with the URL that set it.
+type savedCookie struct {
+	URL      string
+	Name     string
+	Value    string
+	Path     string
+	Domain   string // empty for host-only cookies
+	Expires  time.Time
+	Secure   bool
+	HttpOnly bool
+}
+
+// A sessionJar is a cookie jar that records the cookies set in it, since
+// cookie jars only give back the names and values of their cookies.
+type sessionJar struct {
+	http.CookieJar
+
+	mu      sync.Mutex
+	cookies map[string]*savedCookie // by domain (or host), path, and name
+}
+
+// SetCookies implements http.CookieJar.
+func (j *sessionJar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	j.CookieJar.SetCookies(u, cookies)
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if j.cookies == nil {
+		j.cookies = make(map[string]*savedCookie)
+	}
+	now := time.Now()
+	for _, c := range cookies {
+		saved := &savedCookie{
+			URL:      u.String(),
+			Name:     c.Name,
+			Value:    c.Value,
+			Path:     c.Path,
+			Domain:   c.Domain,
+			Expires:  c.Expires,
+			Secure:   c.Secure,
+			HttpOnly: c.HttpOnly,
+		}
+		if saved.Path == "" || saved.Path[0] != '/' {
+			saved.Path = defaultCookiePath(u.Path)
+		}
+		if c.MaxAge > 0 {
+			saved.Expires = now.Add(time.Duration(c.MaxAge) * time.Second)
+		}
+
+		host := saved.Domain
+		if host == "" {
+			host = u.Host
+		}
+		key := host + ";" + saved.Path + ";" + saved.Name
+		if c.MaxAge < 0 || (!saved.Expires.IsZero() && saved.Expires.Before(now)) {
+			delete(j.cookies, key)
+		} else {
+			j.cookies[key] = saved
+		}
+	}
+}
+
+// saved returns the cookies in j that have not expired.
+func (j *sessionJar) saved() []*savedCookie {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	now := time.Now()
+	cookies := make([]*savedCookie, 0, len(j.cookies))
+	for _, c := range j.cookies {
+		if c.Expires.IsZero() || c.Expires.After(now) {
+			cookies = append(cookies, c)
+		}
+	}
+	return cookies
+}
+
+// defaultCookiePath returns the path of a cookie that was set without one,
+// for a request to urlPath (see RFC 6265, section 5.1.4).
+func defaultCookiePath(urlPath string) string {
+	i := strings.LastIndex(urlPath, "/")
+	if i <= 0 {
+		return "/"
+	}
+	return urlPath[:i]
+}
+
+// seal encrypts plaintext with p.aead, prepending a random nonce.
+func (p *SessionPool) seal(plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, p.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return p.aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// open decrypts data that was encrypted by seal.
+func (p *SessionPool) open(data []byte) ([]byte, error) {
+	size := p.aead.NonceSize()
+	if len(data) < size {
+		return nil, errors.New("ciphertext is too short")
+	}
+	return p.aead.Open(nil, data[:size], data[size:], nil)
+}
+
+// limitedTransport is an http.RoundTripper that makes at most cap(Sem)
+// requests at once.
+type limitedTransport struct {
+	Base http.RoundTripper
+	Sem  chan struct{}
+}
+
+func (lt *limitedTransport) RoundTrip(req *http.Request) (*http.Response,
+	error) {
+	select {
+	case lt.Sem <- struct{}{}:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+	defer func() { <-lt.Sem }()
+	return lt.Base.RoundTrip(req)
+}
@@ -0,0 +1,278 @@
+package uwquest_test
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stevenxie/uwquest"
+)
+
+func TestSessionPool(t *testing.T) {
+	pool, err := uwquest.NewSessionPool(nil)
+	if err != nil {
+		t.Fatalf("Error while creating session pool: %v", err)
+	}
+	if err = pool.Do("nobody", func(*uwquest.Client) error {
+		return nil
+	}); err != uwquest.ErrUnknownUser {
+		t.Errorf("Expected ErrUnknownUser, got: %v", err)
+	}
+
+	user := os.Getenv("QUEST_USER")
+	if err = pool.Add(user, os.Getenv("QUEST_PASS")); err != nil {
+		t.Fatalf("Error while adding account: %v", err)
+	}
+	var terms []*uwquest.Term
+	if err = pool.Do(user, func(c *uwquest.Client) (err error) {
+		terms, err = c.Terms()
+		return err
+	}); err != nil {
+		t.Fatalf("Error while fetching terms through pool: %v", err)
+	}
+	t.Logf("Got terms: %v", terms)
+
+	if err = pool.Remove(user); err != nil {
+		t.Errorf("Error while removing account: %v", err)
+	}
+}
+
+func TestNewSessionPool_BadKey(t *testing.T) {
+	if _, err := uwquest.NewSessionPool(&uwquest.PoolOptions{
+		Key: []byte("too short"),
+	}); err == nil {
+		t.Error("Expected an error for a bad key.")
+	}
+}
+
+func TestNewSessionPool_StoreWithoutKey(t *testing.T) {
+	if _, err := uwquest.NewSessionPool(&uwquest.PoolOptions{
+		Store: new(memorySessionStore),
+	}); err == nil {
+		t.Error("Expected an error for a Store without a Key.")
+	}
+}
+
+// fakeQuest is an http.RoundTripper that imitates Quest's login flow, and
+// records the requests made to it.
+type fakeQuest struct {
+	Delay time.Duration // how long each request takes
+
+	mu                sync.Mutex
+	logins, logouts   int
+	active, maxActive int
+}
+
+func (fq *fakeQuest) RoundTrip(r *http.Request) (*http.Response, error) {
+	fq.mu.Lock()
+	fq.active++
+	if fq.active > fq.maxActive {
+		fq.maxActive = fq.active
+	}
+	fq.mu.Unlock()
+	time.Sleep(fq.Delay)
+
+	fq.mu.Lock()
+	defer fq.mu.Unlock()
+	fq.active--
+
+	res := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     make(http.Header),
+		Body:       ioutil.NopCloser(strings.NewReader("")),
+		Request:    r,
+	}
+	switch {
+	case r.URL.Host == "idp.uwaterloo.ca" && r.Method == http.MethodPost:
+		res.Body = ioutil.NopCloser(strings.NewReader(
+			`<input type="hidden" name="SAMLResponse" value="saml">`))
+	case r.URL.Query().Get("tab") == "DEFAULT":
+		fq.logins++
+		res.Header.Add("Set-Cookie", "PS_TOKEN=token; Path=/; "+
+			"Domain=uwaterloo.ca; Secure; HttpOnly")
+	case r.URL.Query().Get("cmd") == "logout":
+		fq.logouts++
+	}
+	return res, nil
+}
+
+// memorySessionStore is a SessionStore that keeps sessions in memory.
+type memorySessionStore struct {
+	mu       sync.Mutex
+	sessions map[string][]byte
+	deletes  int
+}
+
+func (m *memorySessionStore) LoadSession(user string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.sessions[user], nil
+}
+
+func (m *memorySessionStore) SaveSession(user string, data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.sessions == nil {
+		m.sessions = make(map[string][]byte)
+	}
+	m.sessions[user] = data
+	return nil
+}
+
+func (m *memorySessionStore) DeleteSession(user string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.sessions, user)
+	m.deletes++
+	return nil
+}
+
+// fetch makes a request to Quest with c.
+func fetch(c *uwquest.Client) error {
+	res, err := c.Session.Get(uwquest.StudentCenterURL)
+	if err != nil {
+		return err
+	}
+	return res.Body.Close()
+}
+
+func TestSessionPool_Concurrency(t *testing.T) {
+	quest := &fakeQuest{Delay: 10 * time.Millisecond}
+	pool, err := uwquest.NewSessionPool(&uwquest.PoolOptions{
+		MaxConcurrent: 2,
+		Transport:     quest,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	users := []string{"a", "b", "c"}
+	for _, user := range users {
+		if err := pool.Add(user, "pass"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var (
+		wg     sync.WaitGroup
+		mu     sync.Mutex
+		active = make(map[string]int)
+	)
+	for i := 0; i < 4; i++ {
+		for _, user := range users {
+			wg.Add(1)
+			go func(user string) {
+				defer wg.Done()
+				err := pool.Do(user, func(c *uwquest.Client) error {
+					mu.Lock()
+					active[user]++
+					if active[user] > 1 {
+						t.Errorf("Expected one request at a time for %s", user)
+					}
+					mu.Unlock()
+					defer func() {
+						mu.Lock()
+						active[user]--
+						mu.Unlock()
+					}()
+					return fetch(c)
+				})
+				if err != nil {
+					t.Errorf("Error while fetching for %s: %v", user, err)
+				}
+			}(user)
+		}
+	}
+	wg.Wait()
+
+	if quest.maxActive > 2 {
+		t.Errorf("Expected at most 2 requests at once, got %d", quest.maxActive)
+	}
+	if quest.logins != len(users) {
+		t.Errorf("Expected %d logins, got %d", len(users), quest.logins)
+	}
+}
+
+func TestSessionPool_Store(t *testing.T) {
+	var (
+		quest = new(fakeQuest)
+		store = new(memorySessionStore)
+		opts  = &uwquest.PoolOptions{
+			Key:         make([]byte, 32),
+			Store:       store,
+			Transport:   quest,
+			IdleTimeout: time.Nanosecond,
+		}
+	)
+	pool, err := uwquest.NewSessionPool(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = pool.Add("user", "pass"); err != nil {
+		t.Fatal(err)
+	}
+	if err = pool.Do("user", fetch); err != nil {
+		t.Fatalf("Error while fetching: %v", err)
+	}
+	if quest.logins != 1 || store.sessions["user"] == nil {
+		t.Fatalf("Expected a login, and a saved session, got %d logins",
+			quest.logins)
+	}
+
+	// Another pool with the same key reuses the saved session, with all of
+	// its cookies.
+	other, err := uwquest.NewSessionPool(opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = other.Add("user", "pass"); err != nil {
+		t.Fatal(err)
+	}
+	idp, _ := url.Parse("https://idp.uwaterloo.ca/idp/")
+	if err = other.Do("user", func(c *uwquest.Client) error {
+		if cookies := c.Jar.Cookies(idp); len(cookies) != 1 ||
+			cookies[0].Name != "PS_TOKEN" {
+			t.Errorf("Expected the restored PS_TOKEN cookie, got: %v", cookies)
+		}
+		return fetch(c)
+	}); err != nil {
+		t.Fatalf("Error while fetching with restored session: %v", err)
+	}
+	if quest.logins != 1 {
+		t.Errorf("Expected the saved session to be reused, got %d logins",
+			quest.logins)
+	}
+
+	// Closing a pool keeps its saved sessions.
+	if err = pool.Close(); err != nil {
+		t.Fatalf("Error while closing pool: %v", err)
+	}
+	if quest.logouts != 0 || store.sessions["user"] == nil {
+		t.Errorf("Expected Close to keep the saved session, got %d logouts",
+			quest.logouts)
+	}
+
+	// Idle sessions are logged out.
+	time.Sleep(time.Millisecond)
+	if err = other.EvictIdle(); err != nil {
+		t.Fatalf("Error while evicting idle sessions: %v", err)
+	}
+	if quest.logouts != 1 || store.sessions["user"] != nil {
+		t.Errorf("Expected the idle session to be logged out and deleted, "+
+			"got %d logouts", quest.logouts)
+	}
+
+	// Removing an account that is logged out only deletes its session.
+	store.deletes = 0
+	if err = other.Remove("user"); err != nil {
+		t.Fatalf("Error while removing account: %v", err)
+	}
+	if quest.logouts != 1 || store.deletes != 1 {
+		t.Errorf("Expected 1 logout and 1 deletion, got %d and %d",
+			quest.logouts, store.deletes)
+	}
+}
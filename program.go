@@ -0,0 +1,212 @@
+package uwquest
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	gq "github.com/PuerkitoBio/goquery"
+	ess "github.com/unixpickle/essentials"
+)
+
+// An AcademicProgram is the program that a student is enrolled in, along with
+// their academic progress through it.
+type AcademicProgram struct {
+	Career    string // i.e. "Undergraduate"
+	Program   string // i.e. "Bachelor of Mathematics - Co-op"
+	Faculty   string // i.e. "Faculty of Mathematics"
+	Plans     []*Plan
+	AdmitTerm string // i.e. "Fall 2017"
+
+	// ExpectedGraduation is the term that the student is expected to graduate
+	// in, or blank if Quest does not have one on file.
+	ExpectedGraduation string
+
+	Levels    []*TermLevel
+	Standings []*Standing
+}
+
+func (ap *AcademicProgram) String() string {
+	return fmt.Sprintf("AcademicProgram{Career: %s, Program: %s, Faculty: %s, "+
+		"Plans: %v, AdmitTerm: %s, ExpectedGraduation: %s, Levels: %v, "+
+		"Standings: %v}", ap.Career, ap.Program, ap.Faculty, ap.Plans,
+		ap.AdmitTerm, ap.ExpectedGraduation, ap.Levels, ap.Standings)
+}
+
+// Level returns the student's academic level during the term with the given
+// name (i.e. "Fall 2018"), or blank if Quest has no level for that term.
+func (ap *AcademicProgram) Level(term string) string {
+	for _, level := range ap.Levels {
+		if level.Term == term {
+			return level.Level
+		}
+	}
+	return ""
+}
+
+// PlansOfKind returns the student's plans of a particular kind.
+func (ap *AcademicProgram) PlansOfKind(kind PlanKind) []*Plan {
+	var plans []*Plan
+	for _, plan := range ap.Plans {
+		if plan.Kind == kind {
+			plans = append(plans, plan)
+		}
+	}
+	return plans
+}
+
+// PlanKind is the kind of an academic plan.
+type PlanKind int
+
+// The kinds of academic plans.
+const (
+	UnknownPlan PlanKind = iota
+	Major
+	Minor
+	Option
+	Specialization
+)
+
+func (k PlanKind) String() string {
+	switch k {
+	case UnknownPlan:
+		return "Unknown"
+	case Major:
+		return "Major"
+	case Minor:
+		return "Minor"
+	case Option:
+		return "Option"
+	case Specialization:
+		return "Specialization"
+	default:
+		return fmt.Sprintf("PlanKind(%d)", int(k))
+	}
+}
+
+// parsePlanKind parses a plan type description shown on Quest. Joint and
+// honours majors are both majors, but the more specific kinds are checked
+// first, since descriptions like "Honours Minor" also mention honours.
+func parsePlanKind(text string) PlanKind {
+	text = strings.ToLower(text)
+	switch {
+	case strings.Contains(text, "minor"):
+		return Minor
+	case strings.Contains(text, "option"):
+		return Option
+	case strings.Contains(text, "specialization"):
+		return Specialization
+	case strings.Contains(text, "major"), strings.Contains(text, "joint"),
+		strings.Contains(text, "honours"):
+		return Major
+	default:
+		return UnknownPlan
+	}
+}
+
+// A Plan is a course of study within a program, such as a major or minor.
+type Plan struct {
+	Name string // i.e. "Computer Science"
+	Kind PlanKind
+}
+
+func (p *Plan) String() string {
+	return fmt.Sprintf("Plan{Name: %s, Kind: %s}", p.Name, p.Kind)
+}
+
+// A TermLevel is a student's academic level during a particular term.
+type TermLevel struct {
+	Term  string
+	Level string // i.e. "2B"
+}
+
+func (tl *TermLevel) String() string {
+	return fmt.Sprintf("TermLevel{Term: %s, Level: %s}", tl.Term, tl.Level)
+}
+
+// A Standing is the academic standing decision made at the end of a term.
+type Standing struct {
+	Term     string
+	Decision string // i.e. "Good Standing"
+}
+
+func (s *Standing) String() string {
+	return fmt.Sprintf("Standing{Term: %s, Decision: %s}", s.Term, s.Decision)
+}
+
+// AcademicProgram fetches the student's academic program.
+func (c *Client) AcademicProgram() (*AcademicProgram, error) {
+	page, err := c.fetchPage(AcademicProgramURL)
+	if err != nil {
+		return nil, ess.AddCtx("uwquest: fetching academic program page", err)
+	}
+	program, err := parseAcademicProgram(page.Selection)
+	return program, ess.AddCtx("uwquest: parsing academic program", err)
+}
+
+func parseAcademicProgram(sel *gq.Selection) (*AcademicProgram, error) {
+	program := &AcademicProgram{
+		Career:             fieldText(sel, "UW_SS_PROG_VW_ACAD_CAREER_DESCR"),
+		Program:            fieldText(sel, "UW_SS_PROG_VW_ACAD_PROG_DESCR"),
+		Faculty:            fieldText(sel, "UW_SS_PROG_VW_ACAD_GROUP_DESCR"),
+		AdmitTerm:          fieldText(sel, "UW_SS_PROG_VW_ADMIT_TERM_DESCR"),
+		ExpectedGraduation: fieldText(sel, "UW_SS_PROG_VW_EXP_GRAD_TERM_DESCR"),
+	}
+	if program.Program == "" {
+		return nil, errors.New("could not locate program")
+	}
+
+	var err error
+	sel.Find(`[id^="UW_SS_PLAN_VW_ACAD_PLAN_DESCR$"]`).EachWithBreak(
+		func(i int, name *gq.Selection) bool {
+			var index int
+			if index, err = selectionIndex(name); err != nil {
+				return false
+			}
+			kind := fieldText(sel, fmt.Sprintf("UW_SS_PLAN_VW_PLAN_TYPE_DESCR$%d",
+				index))
+			program.Plans = append(program.Plans, &Plan{
+				Name: strings.TrimSpace(name.Text()),
+				Kind: parsePlanKind(kind),
+			})
+			return true
+		})
+	if err != nil {
+		return nil, ess.AddCtx("parsing plans", err)
+	}
+
+	sel.Find(`[id^="UW_SS_LVL_VW_TERM_DESCR$"]`).EachWithBreak(
+		func(i int, term *gq.Selection) bool {
+			var index int
+			if index, err = selectionIndex(term); err != nil {
+				return false
+			}
+			program.Levels = append(program.Levels, &TermLevel{
+				Term: strings.TrimSpace(term.Text()),
+				Level: fieldText(sel, fmt.Sprintf("UW_SS_LVL_VW_ACAD_LEVEL$%d",
+					index)),
+			})
+			return true
+		})
+	if err != nil {
+		return nil, ess.AddCtx("parsing academic levels", err)
+	}
+
+	sel.Find(`[id^="UW_SS_STAND_VW_TERM_DESCR$"]`).EachWithBreak(
+		func(i int, term *gq.Selection) bool {
+			var index int
+			if index, err = selectionIndex(term); err != nil {
+				return false
+			}
+			program.Standings = append(program.Standings, &Standing{
+				Term: strings.TrimSpace(term.Text()),
+				Decision: fieldText(sel, fmt.Sprintf(
+					"UW_SS_STAND_VW_ACAD_STNDNG_DESCR$%d", index)),
+			})
+			return true
+		})
+	if err != nil {
+		return nil, ess.AddCtx("parsing standings", err)
+	}
+	return program, nil
+}
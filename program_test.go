@@ -0,0 +1,56 @@
+package uwquest_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stevenxie/uwquest"
+)
+
+func TestClient_AcademicProgram(t *testing.T) {
+	program, err := client.AcademicProgram()
+	if err != nil {
+		t.Fatalf("Error while fetching academic program: %v", err)
+	}
+
+	t.Logf("Got academic program: %v", program)
+}
+
+func TestClient_AcademicProgram_PlanKinds(t *testing.T) {
+	cases := []struct {
+		Description string
+		Kind        uwquest.PlanKind
+	}{
+		{"Major", uwquest.Major},
+		{"Honours", uwquest.Major},
+		{"Joint Honours", uwquest.Major},
+		{"Minor", uwquest.Minor},
+		{"Honours Minor", uwquest.Minor},
+		{"Option", uwquest.Option},
+		{"Honours Option", uwquest.Option},
+		{"Specialization", uwquest.Specialization},
+		{"Honours Major Specialization", uwquest.Specialization},
+		{"Diploma", uwquest.UnknownPlan},
+	}
+
+	c, err := uwquest.NewClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range cases {
+		c.Session.Transport = staticPage(fmt.Sprintf("<html><body>"+
+			"<span id='UW_SS_PROG_VW_ACAD_PROG_DESCR'>Bachelor of Mathematics"+
+			"</span><span id='UW_SS_PLAN_VW_ACAD_PLAN_DESCR$0'>Computer Science"+
+			"</span><span id='UW_SS_PLAN_VW_PLAN_TYPE_DESCR$0'>%s</span>"+
+			"</body></html>", tc.Description))
+
+		program, err := c.AcademicProgram()
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", tc.Description, err)
+		}
+		if len(program.Plans) != 1 || program.Plans[0].Kind != tc.Kind {
+			t.Errorf("%s: expected a %s plan, got: %v", tc.Description, tc.Kind,
+				program.Plans)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+package uwquest
+
+import (
+	"fmt"
+
+	ess "github.com/unixpickle/essentials"
+)
+
+// A Threshold is an average that a student is aiming for, such as the
+// average required for Dean's Honours or to keep a scholarship.
+type Threshold struct {
+	Name    string
+	Average float32
+}
+
+func (t Threshold) String() string {
+	return fmt.Sprintf("Threshold{Name: %s, Average: %f}", t.Name, t.Average)
+}
+
+// MathDeansHonours is the term average required for the Faculty of
+// Mathematics' Dean's Honours List. Other faculties set their own
+// requirements, so students in them should use a Threshold of their own.
+var MathDeansHonours = Threshold{Name: "Dean's Honours (Math)", Average: 87}
+
+// WhatIf describes a hypothetical scenario to project averages for.
+type WhatIf struct {
+	// Hypothetical maps the names of outstanding (in progress) courses to
+	// the grades that they are assumed to receive. Each name must be that of
+	// an outstanding course.
+	Hypothetical map[string]float32
+
+	// Thresholds are the averages to check the scenario against. To find
+	// the marks required for a target average, include it as a Threshold.
+	Thresholds []Threshold
+}
+
+// A Projection is the result of projecting averages for a WhatIf scenario.
+type Projection struct {
+	// Average is the average of the known and hypothetical grades.
+	Average *Average
+
+	// Outstanding are the names of the in progress courses that do not
+	// have a hypothetical grade.
+	Outstanding []string
+
+	Thresholds []*ThresholdResult
+}
+
+func (p *Projection) String() string {
+	return fmt.Sprintf("Projection{Average: %v, Outstanding: %v, "+
+		"Thresholds: %v}", p.Average, p.Outstanding, p.Thresholds)
+}
+
+// ThresholdResult describes whether a Threshold can be met in a Projection.
+type ThresholdResult struct {
+	Threshold
+
+	// Met is true if the threshold is met regardless of the marks received
+	// in the outstanding courses.
+	Met bool
+
+	// Attainable is true if the threshold can still be met, given that
+	// grades cannot exceed 100.
+	Attainable bool
+
+	// Required maps the names of outstanding courses to the mark required in
+	// each of them (weighted by units) to meet the threshold. It is nil if
+	// there are no outstanding courses.
+	Required map[string]float32
+}
+
+func (tr *ThresholdResult) String() string {
+	return fmt.Sprintf("ThresholdResult{Threshold: %v, Met: %t, "+
+		"Attainable: %t, Required: %v}", tr.Threshold, tr.Met, tr.Attainable,
+		tr.Required)
+}
+
+// Project projects the average of grades under the scenario described by wi,
+// and determines the marks required in the outstanding courses to meet each
+// of wi's thresholds.
+//
+// Courses are considered outstanding if they are in progress (i.e. have a
+// blank or IP grade) and have units. Project returns an error if a name in
+// wi.Hypothetical is not that of an outstanding course.
+//
+// Only the average of grades is projected, which is a term average when
+// grades are a term's grades. Cumulative averages (and the scholarships that
+// depend on them) are not projected.
+func Project(grades []*CourseGrade, wi *WhatIf) (*Projection, error) {
+	if wi == nil {
+		wi = new(WhatIf)
+	}
+
+	var (
+		projected      = make([]*CourseGrade, 0, len(grades))
+		outstanding    []*CourseGrade
+		remainingUnits float64
+		matched        = make(map[string]bool, len(wi.Hypothetical))
+	)
+	for _, cg := range grades {
+		grade, err := cg.ParsedGrade()
+		if err != nil {
+			return nil, ess.AddCtx(fmt.Sprintf("uwquest: parsing grade for %s",
+				cg.Name), err)
+		}
+		if grade.Kind != InProgressGrade || cg.Units == nil {
+			projected = append(projected, cg)
+			continue
+		}
+
+		if value, ok := wi.Hypothetical[cg.Name]; ok {
+			hypothetical := *cg
+			hypothetical.Grade = fmt.Sprintf("%g", value)
+			projected = append(projected, &hypothetical)
+			matched[cg.Name] = true
+			continue
+		}
+		outstanding = append(outstanding, cg)
+		remainingUnits += float64(*cg.Units)
+	}
+	for name := range wi.Hypothetical {
+		if !matched[name] {
+			return nil, fmt.Errorf("uwquest: no outstanding course named '%s'",
+				name)
+		}
+	}
+
+	avg, err := TermAverage(projected)
+	if err != nil {
+		return nil, err
+	}
+	p := &Projection{Average: avg}
+	for _, cg := range outstanding {
+		p.Outstanding = append(p.Outstanding, cg.Name)
+	}
+
+	for _, threshold := range wi.Thresholds {
+		tr := &ThresholdResult{Threshold: threshold}
+		if remainingUnits == 0 {
+			tr.Met = (avg.Value != nil) && (*avg.Value >= threshold.Average)
+			tr.Attainable = tr.Met
+			p.Thresholds = append(p.Thresholds, tr)
+			continue
+		}
+
+		totalUnits := float64(avg.Units) + remainingUnits
+		required := (float64(threshold.Average)*totalUnits -
+			float64(avg.GradePoints)) / remainingUnits
+		if required <= 0 {
+			required = 0
+			tr.Met = true
+		}
+		tr.Attainable = required <= 100
+
+		tr.Required = make(map[string]float32, len(outstanding))
+		for _, cg := range outstanding {
+			tr.Required[cg.Name] = float32(required)
+		}
+		p.Thresholds = append(p.Thresholds, tr)
+	}
+	return p, nil
+}
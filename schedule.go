@@ -15,7 +15,8 @@ import (
 type CourseSchedule struct {
 	Index        int
 	Name         string
-	Status       string
+	Status       EnrollmentStatus
+	Waitlist     *Waitlist // nil unless Status is Waiting
 	Units        float32
 	GradingBasis string
 	Classes      []*Class
@@ -23,8 +24,57 @@ type CourseSchedule struct {
 
 func (cs *CourseSchedule) String() string {
 	return fmt.Sprintf("CourseSchedule{Index: %d, Name: %s, Status: %s, "+
-		"Units: %f, GradingBasis: %s, Classes: %v}", cs.Index, cs.Name, cs.Status,
-		cs.Units, cs.GradingBasis, cs.Classes)
+		"Waitlist: %v, Units: %f, GradingBasis: %s, Classes: %v}", cs.Index,
+		cs.Name, cs.Status, cs.Waitlist, cs.Units, cs.GradingBasis, cs.Classes)
+}
+
+// EnrollmentStatus is the status of a student's enrollment in a course.
+type EnrollmentStatus int
+
+// The enrollment statuses that Quest reports.
+const (
+	UnknownEnrollment EnrollmentStatus = iota
+	Enrolled
+	Dropped
+	Waiting
+	Withdrawn
+)
+
+var enrollmentStatusNames = [...]string{
+	UnknownEnrollment: "Unknown",
+	Enrolled:          "Enrolled",
+	Dropped:           "Dropped",
+	Waiting:           "Waiting",
+	Withdrawn:         "Withdrawn",
+}
+
+func (s EnrollmentStatus) String() string {
+	if s < 0 || int(s) >= len(enrollmentStatusNames) {
+		return fmt.Sprintf("EnrollmentStatus(%d)", int(s))
+	}
+	return enrollmentStatusNames[s]
+}
+
+// parseEnrollmentStatus parses the status text of a course schedule.
+func parseEnrollmentStatus(text string) EnrollmentStatus {
+	text = strings.TrimSpace(text)
+	for status, name := range enrollmentStatusNames {
+		if name == text {
+			return EnrollmentStatus(status)
+		}
+	}
+	return UnknownEnrollment
+}
+
+// Waitlist describes a student's place on the waitlist for a course.
+type Waitlist struct {
+	Position int // zero if Quest hasn't assigned one yet
+	Reason   string
+}
+
+func (w *Waitlist) String() string {
+	return fmt.Sprintf("Waitlist{Position: %d, Reason: %s}", w.Position,
+		w.Reason)
 }
 
 // Class represents a class within a particular course.
@@ -174,7 +224,13 @@ func parseScheduleTable(table *gq.Selection, classOffset int) (
 	if err != nil {
 		return nil, err
 	}
-	cs.Status = sel.Text()
+	cs.Status = parseEnrollmentStatus(sel.Text())
+
+	if cs.Status == Waiting {
+		if cs.Waitlist, err = parseWaitlist(scraper); err != nil {
+			return nil, ess.AddCtx("parsing waitlist", err)
+		}
+	}
 
 	sel, err = scraper.Find("DERIVED_REGFRM1_UNT_TAKEN", "units taken")
 	if err != nil {
@@ -216,6 +272,31 @@ func parseScheduleTable(table *gq.Selection, classOffset int) (
 	return cs, ess.AddCtx("uwquest: parsing classes table", err)
 }
 
+// parseWaitlist parses the waitlist position and reason from the header row
+// of a waitlisted course's schedule table. Quest leaves out the position of
+// students who haven't been given one yet, in which case it is zero.
+func parseWaitlist(scraper indexedScraper) (*Waitlist, error) {
+	w := new(Waitlist)
+	sel, err := scraper.Find("STDNT_ENRL_SSV2_STDNT_POSITIN",
+		"waitlist position")
+	if err == nil {
+		if text := strings.TrimSpace(strings.Replace(sel.Text(), "\u00a0", "",
+			-1)); text != "" {
+			if w.Position, err = strconv.Atoi(text); err != nil {
+				return nil, ess.AddCtx("could not parse waitlist position into int",
+					err)
+			}
+		}
+	}
+
+	// Quest does not always give a reason for waitlisting.
+	if sel, err = scraper.Find("DERIVED_SSE_DSP_WAITLIST_REASON",
+		"waitlist reason"); err == nil {
+		w.Reason = strings.TrimSpace(sel.Text())
+	}
+	return w, nil
+}
+
 // parseClassRow parses a class row within a course schedule table into a
 // Class.
 func parseClassRow(row *gq.Selection, offset int) (*Class, error) {
@@ -2,6 +2,8 @@ package uwquest_test
 
 import (
 	"testing"
+
+	"github.com/stevenxie/uwquest"
 )
 
 func TestClient_Schedules(t *testing.T) {
@@ -13,6 +15,14 @@ func TestClient_Schedules(t *testing.T) {
 	if len(s) == 0 {
 		t.Fatal("Did not find any course schedules for term 0.")
 	}
+	for _, cs := range s {
+		if cs.Status == uwquest.UnknownEnrollment {
+			t.Errorf("Found course schedule with an unknown status: %v", cs)
+		}
+		if (cs.Status == uwquest.Waiting) != (cs.Waitlist != nil) {
+			t.Errorf("Found course schedule with a mismatched waitlist: %v", cs)
+		}
+	}
 
 	t.Logf("Got course schedules for term 0: %v\n", s)
 }
@@ -4,7 +4,10 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
 
 	gq "github.com/PuerkitoBio/goquery"
 	ess "github.com/unixpickle/essentials"
@@ -17,7 +20,13 @@ func scrapeHiddenFields(body io.Reader) (url.Values, error) {
 	if err != nil {
 		return nil, ess.AddCtx("parsing Quest homepage with goquery", err)
 	}
-	sel := doc.Find("#win0divPSHIDDENFIELDS")
+	return hiddenFields(doc.Selection)
+}
+
+// hiddenFields returns the hidden fields of an already-parsed Quest page as a
+// url.Values.
+func hiddenFields(sel *gq.Selection) (url.Values, error) {
+	sel = sel.Find("#win0divPSHIDDENFIELDS")
 	if sel.Length() != 1 {
 		return nil, errors.New("could not find hidden fields on Quest homepage")
 	}
@@ -34,6 +43,86 @@ func scrapeHiddenFields(body io.Reader) (url.Values, error) {
 	return fields, nil
 }
 
+// fetchPage fetches the Quest page at url, and parses it with goquery.
+func (c *Client) fetchPage(url string) (*gq.Document, error) {
+	res, err := c.Session.Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("got non-200 status code: got code %d",
+			res.StatusCode)
+	}
+
+	doc, err := gq.NewDocumentFromReader(res.Body)
+	if err != nil {
+		return nil, ess.AddCtx("parsing response body with goquery", err)
+	}
+	return doc, ess.AddCtx("closing response body", res.Body.Close())
+}
+
+// pageURL returns the URL that page's form is submitted to, which is where
+// PeopleSoft expects the page's actions to be posted. base is the URL that
+// page was fetched from; relative form actions are resolved against it, and it
+// is returned if page's form has no action.
+func pageURL(page *gq.Document, base string) string {
+	action, ok := page.Find(`form[name="win0"]`).Attr("action")
+	if !ok || strings.TrimSpace(action) == "" {
+		return base
+	}
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return base
+	}
+	u, err := baseURL.Parse(strings.TrimSpace(action))
+	if err != nil {
+		return base
+	}
+	return u.String()
+}
+
+// submitPage submits a Quest page with the hidden fields scraped from page,
+// the given PeopleSoft action, and any additional form fields. The resulting
+// page is parsed with goquery.
+//
+// Unlike the AJAX requests made by Grades and Schedules, the response is a
+// full page, so that its hidden fields can be used to continue multi-step
+// PeopleSoft flows.
+func (c *Client) submitPage(url string, page *gq.Document, action string,
+	fields url.Values) (*gq.Document, error) {
+	form, err := hiddenFields(page.Selection)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range fields {
+		form[name] = values
+	}
+	form.Set("ICAction", action)
+
+	req, err := http.NewRequest("POST", url, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, ess.AddCtx("creating request", err)
+	}
+	req.Header.Add("Content-Type", "application/x-www-form-urlencoded")
+
+	res, err := c.Session.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("got non-200 status code: got code %d",
+			res.StatusCode)
+	}
+
+	doc, err := gq.NewDocumentFromReader(res.Body)
+	if err != nil {
+		return nil, ess.AddCtx("parsing response body with goquery", err)
+	}
+	return doc, ess.AddCtx("closing response body", res.Body.Close())
+}
+
 type indexedScraper struct {
 	Index int
 	Sel   *gq.Selection
@@ -46,3 +135,77 @@ func (is indexedScraper) Find(id, desc string) (*gq.Selection, error) {
 	}
 	return sel, nil
 }
+
+// parseOptionalFloat parses text into a float32, returning nil if text is
+// blank (as Quest renders empty cells with a non-breaking space).
+func parseOptionalFloat(text string) (*float32, error) {
+	const nbsp = "\u00a0"
+	if text = strings.TrimSpace(strings.Replace(text, nbsp, "", -1)); text == "" {
+		return nil, nil
+	}
+	f64, err := strconv.ParseFloat(text, 32)
+	if err != nil {
+		return nil, err
+	}
+	f32 := float32(f64)
+	return &f32, nil
+}
+
+// textLines returns the non-blank lines of text within sel, where lines are
+// separated by elements such as <br>.
+func textLines(sel *gq.Selection) []string {
+	const nbsp = "\u00a0"
+	var lines []string
+	sel.Contents().Each(func(_ int, node *gq.Selection) {
+		if gq.NodeName(node) != "#text" {
+			lines = append(lines, textLines(node)...)
+			return
+		}
+		text := strings.TrimSpace(strings.Replace(node.Text(), nbsp, " ", -1))
+		if text != "" {
+			lines = append(lines, text)
+		}
+	})
+	return lines
+}
+
+// selectionIndex parses the index suffix of a PeopleSoft element ID, i.e. the
+// 3 in "MTG_CLASS_NBR$3".
+func selectionIndex(sel *gq.Selection) (int, error) {
+	id, ok := sel.Attr("id")
+	if !ok {
+		return 0, errors.New("element does not have an 'id' attribute")
+	}
+	i := strings.LastIndexByte(id, '$')
+	if i == -1 {
+		return 0, fmt.Errorf("element ID '%s' has no index", id)
+	}
+	return strconv.Atoi(id[i+1:])
+}
+
+// viewAll expands every grid on page that PeopleSoft has split into chunks, by
+// following each grid's "View All" link.
+func (c *Client) viewAll(pageURL string, page *gq.Document) (*gq.Document,
+	error) {
+	for expanded := make(map[string]bool); ; {
+		var action string
+		page.Find(`a[id*="$hviewall$"]`).EachWithBreak(
+			func(_ int, link *gq.Selection) bool {
+				id, _ := link.Attr("id")
+				if expanded[id] || strings.TrimSpace(link.Text()) != "View All" {
+					return true // continue
+				}
+				action = id
+				return false
+			})
+		if action == "" {
+			return page, nil
+		}
+
+		expanded[action] = true
+		var err error
+		if page, err = c.submitPage(pageURL, page, action, nil); err != nil {
+			return nil, err
+		}
+	}
+}
@@ -0,0 +1,364 @@
+package uwquest
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	gq "github.com/PuerkitoBio/goquery"
+	ess "github.com/unixpickle/essentials"
+)
+
+// ClassStatus is the enrollment status of a class section.
+type ClassStatus int
+
+// The class statuses that Quest reports.
+const (
+	UnknownStatus ClassStatus = iota
+	OpenStatus
+	ClosedStatus
+	WaitlistStatus
+)
+
+func (s ClassStatus) String() string {
+	switch s {
+	case OpenStatus:
+		return "Open"
+	case ClosedStatus:
+		return "Closed"
+	case WaitlistStatus:
+		return "Wait List"
+	default:
+		return "Unknown"
+	}
+}
+
+// parseClassStatus parses the status icon of a class section, which PeopleSoft
+// labels using the icon's 'alt' attribute.
+func parseClassStatus(sel *gq.Selection) ClassStatus {
+	alt, _ := sel.Find("img").Attr("alt")
+	switch strings.TrimSpace(alt) {
+	case "Open":
+		return OpenStatus
+	case "Closed":
+		return ClosedStatus
+	case "Wait List":
+		return WaitlistStatus
+	default:
+		return UnknownStatus
+	}
+}
+
+// ClassQuery describes the criteria for a class search.
+//
+// Subject is required by Quest, unless ClassNumber is set; all other fields
+// are optional.
+type ClassQuery struct {
+	Subject       string // i.e. "CS"
+	CatalogNumber string // i.e. "135"
+	ClassNumber   int    // i.e. 5123; zero matches any class
+	Career        string // i.e. "UG" (undergraduate), "GRD" (graduate)
+	OpenOnly      bool
+	Component     string // i.e. "LEC", "TUT", "LAB"
+	Instructor    string // matches instructor last names containing this
+}
+
+// ClassSection represents a class section found with SearchClasses.
+type ClassSection struct {
+	Course    string // i.e. "CS 135"
+	Title     string
+	Number    int
+	Section   int
+	Component string
+	Status    ClassStatus
+	Meetings  []*Meeting
+
+	// Enrollment capacity and totals, which may be nil if Quest does not
+	// show them.
+	Capacity      *int
+	Total         *int
+	WaitlistTotal *int
+}
+
+func (cs *ClassSection) String() string {
+	sb := new(strings.Builder)
+	fmt.Fprintf(sb, "ClassSection{Course: %s, Title: %s, Number: %d, "+
+		"Section: %d, Component: %s, Status: %s, Meetings: %v", cs.Course,
+		cs.Title, cs.Number, cs.Section, cs.Component, cs.Status, cs.Meetings)
+	for _, field := range []struct {
+		Name  string
+		Value *int
+	}{
+		{"Capacity", cs.Capacity},
+		{"Total", cs.Total},
+		{"WaitlistTotal", cs.WaitlistTotal},
+	} {
+		if field.Value == nil {
+			fmt.Fprintf(sb, ", %s: <nil>", field.Name)
+		} else {
+			fmt.Fprintf(sb, ", %s: %d", field.Name, *field.Value)
+		}
+	}
+	sb.WriteByte('}')
+	return sb.String()
+}
+
+// Meeting represents a scheduled meeting of a class section.
+type Meeting struct {
+	Schedule   string
+	Location   string
+	Instructor string
+}
+
+func (m *Meeting) String() string {
+	return fmt.Sprintf("Meeting{Schedule: %s, Location: %s, Instructor: %s}",
+		m.Schedule, m.Location, m.Instructor)
+}
+
+// ErrTooManyResults is returned by SearchClasses when a search matches more
+// classes than Quest is willing to display.
+var ErrTooManyResults = errors.New("uwquest: class search returned too many " +
+	"results (narrow the search criteria)")
+
+// SearchClasses searches for class sections offered during a particular term,
+// where termIndex is the index of a term returned by TermsWithSchedule.
+//
+// It returns nil if no classes match query.
+func (c *Client) SearchClasses(termIndex int, query *ClassQuery) (
+	[]*ClassSection, error) {
+	terms, err := c.TermsWithSchedule()
+	if err != nil {
+		return nil, err
+	}
+	code, err := termCode(terms, termIndex)
+	if err != nil {
+		return nil, err
+	}
+	return c.SearchClassesByCode(code, query)
+}
+
+// SearchClassesByCode is like SearchClasses, but identifies the term by its
+// code (see Term.Code). Unlike SearchClasses, it doesn't need to fetch the
+// list of terms, so it is better suited to repeated searches.
+func (c *Client) SearchClassesByCode(code string, query *ClassQuery) (
+	[]*ClassSection, error) {
+
+	page, err := c.fetchPage(ClassSearchURL)
+	if err != nil {
+		return nil, ess.AddCtx("uwquest: fetching class search page", err)
+	}
+
+	// Submit the search form.
+	form := make(url.Values)
+	form.Set("CLASS_SRCH_WRK2_STRM$35$", code)
+	if query.Subject != "" {
+		form.Set("SSR_CLSRCH_WRK_SUBJECT_SRCH$0", query.Subject)
+	}
+	if query.CatalogNumber != "" {
+		form.Set("SSR_CLSRCH_WRK_SSR_EXACT_MATCH1$1", "E")
+		form.Set("SSR_CLSRCH_WRK_CATALOG_NBR$1", query.CatalogNumber)
+	}
+	if query.Career != "" {
+		form.Set("SSR_CLSRCH_WRK_ACAD_CAREER$2", query.Career)
+	}
+	form.Set("SSR_CLSRCH_WRK_SSR_OPEN_ONLY$chk$3", "N")
+	if query.OpenOnly {
+		form.Set("SSR_CLSRCH_WRK_SSR_OPEN_ONLY$chk$3", "Y")
+		form.Set("SSR_CLSRCH_WRK_SSR_OPEN_ONLY$3", "Y")
+	}
+	if query.Component != "" {
+		form.Set("SSR_CLSRCH_WRK_SSR_COMPONENT$4", query.Component)
+	}
+	if query.Instructor != "" {
+		form.Set("SSR_CLSRCH_WRK_SSR_EXACT_MATCH2$5", "C")
+		form.Set("SSR_CLSRCH_WRK_LAST_NAME$5", query.Instructor)
+	}
+	if query.ClassNumber != 0 {
+		form.Set("SSR_CLSRCH_WRK_CLASS_NBR$8", strconv.Itoa(query.ClassNumber))
+	}
+
+	page, err = c.submitPage(ClassSearchURL, page,
+		"CLASS_SRCH_WRK2_SSR_PB_CLASS_SRCH", form)
+	if err != nil {
+		return nil, ess.AddCtx("uwquest: submitting class search", err)
+	}
+
+	// Check for search messages, and confirm large searches.
+	if msg := strings.TrimSpace(page.Find(`#DERIVED_CLSMSG_ERROR_TEXT`).
+		Text()); msg != "" {
+		if strings.Contains(msg, "no results") {
+			return nil, nil
+		}
+		if strings.Contains(msg, "maximum") {
+			return nil, ErrTooManyResults
+		}
+		return nil, fmt.Errorf("uwquest: class search failed: %s", msg)
+	}
+	if page.Find(`#DERIVED_SSE_DSP_SSR_MSG_TEXT`).Length() > 0 {
+		if page, err = c.submitPage(ClassSearchURL, page, "#ICSave",
+			nil); err != nil {
+			return nil, ess.AddCtx("uwquest: confirming class search", err)
+		}
+	}
+
+	// PeopleSoft only shows the first few sections of each course.
+	if page, err = c.viewAll(ClassSearchURL, page); err != nil {
+		return nil, ess.AddCtx("uwquest: expanding class search results", err)
+	}
+
+	sections, err := parseSearchResults(page.Selection)
+	return sections, ess.AddCtx("uwquest: parsing class search results", err)
+}
+
+// courseHeaderRegexp matches the course headers in class search results, i.e.
+// "CS  135 - Designing Functional Programs".
+var courseHeaderRegexp = regexp.MustCompile(`^(\S+)\s+(\S+)\s+-\s+(.*)$`)
+
+// parseSearchResults parses the course groups of the class search results page
+// into ClassSections.
+func parseSearchResults(sel *gq.Selection) ([]*ClassSection, error) {
+	const nbsp = "\u00a0"
+	groups := sel.Find(`div[id^="win0divSSR_CLSRSLT_WRK_GROUPBOX2$"]`)
+	if groups.Length() == 0 {
+		return nil, errors.New("could not find course groups")
+	}
+
+	var (
+		sections []*ClassSection
+		err      error
+	)
+	groups.EachWithBreak(func(i int, group *gq.Selection) bool {
+		header := strings.TrimSpace(group.Find(`[id^="DERIVED_CLSRCH_DESCR200$"]`).
+			First().Text())
+		header = strings.Replace(header, nbsp, " ", -1)
+		match := courseHeaderRegexp.FindStringSubmatch(header)
+		if match == nil {
+			err = fmt.Errorf("group %d: unexpected course header '%s'", i, header)
+			return false
+		}
+
+		group.Find(`[id^="MTG_CLASS_NBR$"]`).EachWithBreak(
+			func(_ int, nbr *gq.Selection) bool {
+				var index int
+				if index, err = selectionIndex(nbr); err != nil {
+					return false
+				}
+
+				var cs *ClassSection
+				if cs, err = parseSearchSection(group, index); err != nil {
+					ess.AddCtxTo(fmt.Sprintf("section %d", index), &err)
+					return false
+				}
+				cs.Course = match[1] + " " + match[2]
+				cs.Title = match[3]
+
+				sections = append(sections, cs)
+				return true
+			})
+		if err != nil {
+			ess.AddCtxTo(fmt.Sprintf("group %d", i), &err)
+			return false
+		}
+		return true
+	})
+	return sections, err
+}
+
+// classNameRegexp matches the section names of class search results, i.e.
+// "001-LEC".
+var classNameRegexp = regexp.MustCompile(`^(\d+)-(\S+)`)
+
+func parseSearchSection(group *gq.Selection, index int) (*ClassSection,
+	error) {
+	const nbsp = "\u00a0"
+	var (
+		cs       = new(ClassSection)
+		scraper  = indexedScraper{Index: index, Sel: group}
+		sel, err = scraper.Find("MTG_CLASS_NBR", "class number")
+	)
+	if err != nil {
+		return nil, err
+	}
+	if cs.Number, err = strconv.Atoi(strings.TrimSpace(sel.Text())); err != nil {
+		return nil, ess.AddCtx("could not parse class number into int", err)
+	}
+
+	if sel, err = scraper.Find("MTG_CLASSNAME", "section name"); err != nil {
+		return nil, err
+	}
+	lines := textLines(sel)
+	if len(lines) == 0 {
+		return nil, errors.New("section name is blank")
+	}
+	match := classNameRegexp.FindStringSubmatch(lines[0])
+	if match == nil {
+		return nil, fmt.Errorf("unexpected section name '%s'", lines[0])
+	}
+	cs.Section, _ = strconv.Atoi(match[1])
+	cs.Component = match[2]
+
+	sel, err = scraper.Find("DERIVED_CLSRCH_SSR_STATUS_LONG", "class status")
+	if err != nil {
+		return nil, err
+	}
+	cs.Status = parseClassStatus(sel)
+
+	// Parse meetings, which PeopleSoft displays as lines within each cell.
+	var columns [3][]string
+	for i, field := range []struct{ ID, Desc string }{
+		{"MTG_DAYTIME", "class schedule"},
+		{"MTG_ROOM", "class location"},
+		{"MTG_INSTR", "instructor"},
+	} {
+		if sel, err = scraper.Find(field.ID, field.Desc); err != nil {
+			return nil, err
+		}
+		columns[i] = textLines(sel)
+	}
+	for i := 0; ; i++ {
+		var (
+			m    = new(Meeting)
+			done = true
+		)
+		for j, dest := range []*string{&m.Schedule, &m.Location,
+			&m.Instructor} {
+			if i < len(columns[j]) {
+				*dest = columns[j][i]
+				done = false
+			}
+		}
+		if done {
+			break
+		}
+		cs.Meetings = append(cs.Meetings, m)
+	}
+
+	// Parse enrollment totals, which are only shown for some searches.
+	for _, field := range []struct {
+		ID   string
+		Dest **int
+	}{
+		{"UW_DERIVED_SR_ENRL_CAP", &cs.Capacity},
+		{"UW_DERIVED_SR_ENRL_TOT", &cs.Total},
+		{"UW_DERIVED_SR_WAIT_TOT", &cs.WaitlistTotal},
+	} {
+		if sel, err = scraper.Find(field.ID, "enrollment total"); err != nil {
+			continue
+		}
+		text := strings.TrimSpace(strings.Replace(sel.Text(), nbsp, "", -1))
+		if text == "" {
+			continue
+		}
+		n, err := strconv.Atoi(text)
+		if err != nil {
+			return nil, ess.AddCtx("could not parse enrollment total into int",
+				err)
+		}
+		*field.Dest = &n
+	}
+
+	return cs, nil
+}
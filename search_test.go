@@ -0,0 +1,27 @@
+package uwquest_test
+
+import (
+	"testing"
+
+	"github.com/stevenxie/uwquest"
+)
+
+func TestClient_SearchClasses(t *testing.T) {
+	terms, err := client.TermsWithSchedule()
+	if err != nil {
+		t.Fatalf("Error while fetching terms data: %v", err)
+	}
+	if len(terms) == 0 {
+		t.Fatal("Did not find any terms.")
+	}
+
+	sections, err := client.SearchClasses(terms[0].Index, &uwquest.ClassQuery{
+		Subject:       "CS",
+		CatalogNumber: "135",
+	})
+	if err != nil {
+		t.Fatalf("Error while searching for classes: %v", err)
+	}
+
+	t.Logf("Got class sections for CS 135: %v", sections)
+}
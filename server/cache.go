@@ -0,0 +1,72 @@
+package server
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// An entry is a cached JSON response.
+type entry struct {
+	Value   interface{} // the value that Body encodes
+	Body    []byte
+	ETag    string // a strong ETag, including its quotes
+	Fetched time.Time
+}
+
+func newEntry(v interface{}, fetched time.Time) (*entry, error) {
+	body, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	body = append(body, '\n')
+	sum := sha256.Sum256(body)
+	return &entry{
+		Value:   v,
+		Body:    body,
+		ETag:    `"` + hex.EncodeToString(sum[:16]) + `"`,
+		Fetched: fetched,
+	}, nil
+}
+
+// serve writes e, or http.StatusNotModified if r already has it (according to
+// its If-None-Match header).
+func (e *entry) serve(w http.ResponseWriter, r *http.Request,
+	ttl time.Duration) {
+	maxAge := ttl - time.Since(e.Fetched)
+	if maxAge < 0 {
+		maxAge = 0
+	}
+	header := w.Header()
+	header.Set("ETag", e.ETag)
+	header.Set("Cache-Control", "private, max-age="+
+		strconv.Itoa(int(maxAge/time.Second)))
+	header.Set("Last-Modified", e.Fetched.UTC().Format(http.TimeFormat))
+
+	if etagMatches(r.Header.Get("If-None-Match"), e.ETag) {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	header.Set("Content-Type", "application/json")
+	header.Set("Content-Length", strconv.Itoa(len(e.Body)))
+	w.WriteHeader(http.StatusOK)
+	if r.Method != http.MethodHead {
+		w.Write(e.Body)
+	}
+}
+
+// etagMatches reports whether an If-None-Match header matches etag. Weak
+// ETags in the header match their strong counterparts.
+func etagMatches(header, etag string) bool {
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == etag || tag == "*" {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,316 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stevenxie/uwquest/notify"
+	"github.com/stevenxie/uwquest/store"
+	ess "github.com/unixpickle/essentials"
+)
+
+// DefaultHeartbeat is the default time between heartbeats on an event
+// stream.
+const DefaultHeartbeat = 15 * time.Second
+
+// subscriberBuffer is the number of events that can be waiting to be sent to
+// a subscriber. Subscribers that fall further behind are disconnected, and
+// catch up when they reconnect (using Last-Event-ID).
+const subscriberBuffer = 64
+
+// An EventLog keeps the events that a Broker is notified of, so that clients
+// can resume their streams after disconnecting. It is implemented by
+// *store.Store.
+type EventLog interface {
+	AppendEvent(e notify.Event) (*store.LoggedEvent, error)
+	EventsSince(id int64) ([]*store.LoggedEvent, error)
+}
+
+var _ EventLog = (*store.Store)(nil)
+
+// A Broker is a notify.Notifier that streams the events it is notified of
+// (such as the changes found by a watcher) to HTTP clients, as server-sent
+// events:
+//
+//	id: 42
+//	event: grade_posted
+//	data: {"id":42,"time":"...","type":"grade_posted","term":"Fall 2018",...}
+//
+// Clients can limit the events they receive to particular terms and courses
+// with the "term" and "course" query parameters, which may be repeated. They
+// can resume a stream by sending the ID of the last event they received in
+// the Last-Event-ID header (or the "lastEventId" query parameter), and a
+// comment is sent on each stream every so often as a heartbeat.
+type Broker struct {
+	// Log is where events are kept for clients that resume their streams. If
+	// it is nil, the latest events are kept in memory.
+	Log EventLog
+
+	// Heartbeat is the time between heartbeats, which defaults to
+	// DefaultHeartbeat.
+	Heartbeat time.Duration
+
+	mu     sync.Mutex
+	mem    *memoryLog
+	subs   map[*subscriber]bool
+	closed bool
+}
+
+var (
+	_ notify.Notifier = (*Broker)(nil)
+	_ http.Handler    = (*Broker)(nil)
+)
+
+// A subscriber is a client that is streaming events.
+type subscriber struct {
+	Events chan *store.LoggedEvent
+	Filter *filter
+}
+
+// Notify implements notify.Notifier, by logging e and sending it to each
+// matching subscriber.
+//
+// If e can't be logged, it isn't sent to anyone and Notify fails, so that
+// watchers don't save the changes that e came from, and deliver it again
+// after their next poll. This way, every event that clients see can also be
+// resumed from.
+func (b *Broker) Notify(_ context.Context, e notify.Event) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	le, err := b.log().AppendEvent(e)
+	if err != nil {
+		return ess.AddCtx("server: logging event", err)
+	}
+	for sub := range b.subs {
+		if !sub.Filter.Matches(le) {
+			continue
+		}
+		select {
+		case sub.Events <- le:
+		default:
+			// Drop subscribers that have fallen behind.
+			b.unsubscribe(sub)
+		}
+	}
+	return nil
+}
+
+// Close ends every stream, and stops new ones from starting. It is meant to
+// be called when shutting down an http.Server (see
+// http.Server.RegisterOnShutdown), since streams don't otherwise end.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for sub := range b.subs {
+		b.unsubscribe(sub)
+	}
+}
+
+// ServeHTTP implements http.Handler, by streaming events to the client until
+// it disconnects.
+func (b *Broker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "streaming unsupported")
+		return
+	}
+
+	var (
+		lastID int64
+		resume bool
+		err    error
+	)
+	query := r.URL.Query()
+	id := r.Header.Get("Last-Event-ID")
+	if id == "" {
+		id = query.Get("lastEventId")
+	}
+	if id != "" {
+		resume = true
+		if lastID, err = strconv.ParseInt(id, 10, 64); err != nil {
+			writeError(w, http.StatusBadRequest, fmt.Sprintf("bad event ID '%s'",
+				id))
+			return
+		}
+	}
+
+	sub := &subscriber{
+		Events: make(chan *store.LoggedEvent, subscriberBuffer),
+		Filter: &filter{Terms: query["term"], Courses: query["course"]},
+	}
+	if !b.subscribe(sub) {
+		writeError(w, http.StatusServiceUnavailable, "shutting down")
+		return
+	}
+	defer func() {
+		b.mu.Lock()
+		b.unsubscribe(sub)
+		b.mu.Unlock()
+	}()
+
+	// Read missed events after subscribing, so that none are lost in between;
+	// events that are both missed and received are only sent once.
+	var missed []*store.LoggedEvent
+	if resume {
+		b.mu.Lock()
+		missed, err = b.log().EventsSince(lastID)
+		b.mu.Unlock()
+		if err != nil {
+			writeError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+	}
+
+	header := w.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("X-Accel-Buffering", "no") // stop proxies from buffering
+	w.WriteHeader(http.StatusOK)
+
+	send := func(le *store.LoggedEvent) error {
+		if le.ID <= lastID || !sub.Filter.Matches(le) {
+			return nil
+		}
+		lastID = le.ID
+		return writeEvent(w, le)
+	}
+	for _, le := range missed {
+		if err := send(le); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	ticker := time.NewTicker(b.heartbeat())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case le, ok := <-sub.Events:
+			if !ok {
+				return
+			}
+			if err := send(le); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+func (b *Broker) subscribe(sub *subscriber) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return false
+	}
+	if b.subs == nil {
+		b.subs = make(map[*subscriber]bool)
+	}
+	b.subs[sub] = true
+	return true
+}
+
+// unsubscribe removes sub, and closes its channel. b.mu must be held.
+func (b *Broker) unsubscribe(sub *subscriber) {
+	if b.subs[sub] {
+		delete(b.subs, sub)
+		close(sub.Events)
+	}
+}
+
+// log returns b.Log, or an in-memory log if it is nil. b.mu must be held.
+func (b *Broker) log() EventLog {
+	if b.Log != nil {
+		return b.Log
+	}
+	if b.mem == nil {
+		b.mem = new(memoryLog)
+	}
+	return b.mem
+}
+
+func (b *Broker) heartbeat() time.Duration {
+	if b.Heartbeat <= 0 {
+		return DefaultHeartbeat
+	}
+	return b.Heartbeat
+}
+
+// writeEvent writes le to w as a server-sent event.
+func writeEvent(w http.ResponseWriter, le *store.LoggedEvent) error {
+	data, err := json.Marshal(le)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", le.ID, le.Type,
+		data)
+	return err
+}
+
+// A filter selects the events that a client wants. Empty lists match
+// everything.
+type filter struct {
+	Terms, Courses []string
+}
+
+// Matches reports whether le passes f.
+func (f *filter) Matches(le *store.LoggedEvent) bool {
+	return matchesAny(f.Terms, le.Term) && matchesAny(f.Courses, le.Course)
+}
+
+// matchesAny reports whether s is in list, ignoring case, or if list is
+// empty.
+func matchesAny(list []string, s string) bool {
+	if len(list) == 0 {
+		return true
+	}
+	for _, item := range list {
+		if strings.EqualFold(strings.TrimSpace(item), s) {
+			return true
+		}
+	}
+	return false
+}
+
+// memoryLog is an EventLog that keeps the latest store.DefaultMaxEvents
+// events in memory. It is guarded by its Broker's mutex.
+type memoryLog struct {
+	lastID int64
+	events []*store.LoggedEvent
+}
+
+func (ml *memoryLog) AppendEvent(e notify.Event) (*store.LoggedEvent, error) {
+	le, err := store.NewLoggedEvent(ml.lastID+1, e, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	ml.lastID = le.ID
+	ml.events = append(ml.events, le)
+	if len(ml.events) > store.DefaultMaxEvents {
+		ml.events = ml.events[len(ml.events)-store.DefaultMaxEvents:]
+	}
+	return le, nil
+}
+
+func (ml *memoryLog) EventsSince(id int64) ([]*store.LoggedEvent, error) {
+	for i, le := range ml.events {
+		if le.ID > id {
+			return ml.events[i:], nil
+		}
+	}
+	return nil, nil
+}
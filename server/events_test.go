@@ -0,0 +1,118 @@
+package server_test
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stevenxie/uwquest/diff"
+	"github.com/stevenxie/uwquest/notify"
+	"github.com/stevenxie/uwquest/server"
+	"github.com/stevenxie/uwquest/store"
+)
+
+// readEvent reads the next event (or comment) from an event stream.
+func readEvent(t *testing.T, r *bufio.Reader) string {
+	var lines []string
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Error while reading stream: %v", err)
+		}
+		if line == "\n" {
+			return strings.Join(lines, "")
+		}
+		lines = append(lines, line)
+	}
+}
+
+func gradePosted(term, course, grade string) *diff.GradePosted {
+	return &diff.GradePosted{
+		Key:   diff.Key{Term: term, Course: course},
+		Grade: grade,
+	}
+}
+
+func TestBroker(t *testing.T) {
+	var (
+		broker = &server.Broker{Heartbeat: 50 * time.Millisecond}
+		srv    = httptest.NewServer(&server.Server{
+			Quest:  new(fakeQuest),
+			Events: broker,
+		})
+		ctx = context.Background()
+	)
+	defer srv.Close()
+	defer broker.Close()
+
+	for _, e := range []*diff.GradePosted{
+		gradePosted("Fall 2018", "CS 135", "85"),   // 1
+		gradePosted("Winter 2018", "CS 136", "80"), // 2 (filtered out)
+		gradePosted("Fall 2018", "MATH 135", "90"), // 3
+	} {
+		if err := broker.Notify(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// Resume after the first event, watching only Fall 2018.
+	req, err := http.NewRequest(http.MethodGet,
+		srv.URL+"/events?term=Fall+2018", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Last-Event-ID", "1")
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	if ct := res.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Expected an event stream, got: %s", ct)
+	}
+	r := bufio.NewReader(res.Body)
+
+	if e := readEvent(t, r); !strings.HasPrefix(e,
+		"id: 3\nevent: grade_posted\ndata: ") ||
+		!strings.Contains(e, `"course":"MATH 135"`) {
+		t.Errorf("Expected missed event 3, got: %q", e)
+	}
+
+	if err = broker.Notify(ctx, gradePosted("Fall 2018", "CS 135",
+		"95")); err != nil {
+		t.Fatal(err)
+	}
+	if e := readEvent(t, r); !strings.HasPrefix(e, "id: 4\n") {
+		t.Errorf("Expected live event 4, got: %q", e)
+	}
+
+	if e := readEvent(t, r); e != ": heartbeat\n" {
+		t.Errorf("Expected a heartbeat, got: %q", e)
+	}
+}
+
+// failingLog is an EventLog that can't be written to.
+type failingLog struct{}
+
+func (failingLog) AppendEvent(notify.Event) (*store.LoggedEvent, error) {
+	return nil, errors.New("disk full")
+}
+
+func (failingLog) EventsSince(int64) ([]*store.LoggedEvent, error) {
+	return nil, nil
+}
+
+func TestBroker_NotifyLogError(t *testing.T) {
+	broker := &server.Broker{Log: failingLog{}}
+	defer broker.Close()
+
+	e := gradePosted("Fall 2018", "CS 135", "85")
+	if err := broker.Notify(context.Background(), e); err == nil {
+		t.Error("Expected an error when the event can't be logged.")
+	}
+}
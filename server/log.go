@@ -0,0 +1,40 @@
+package server
+
+import (
+	"log"
+	"net/http"
+	"time"
+)
+
+// Logged wraps h so that each request is logged to logger (or the standard
+// logger, if logger is nil), along with its status and duration.
+func Logged(h http.Handler, logger *log.Logger) http.Handler {
+	if logger == nil {
+		logger = log.New(log.Writer(), log.Prefix(), log.Flags())
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		sw := &statusWriter{ResponseWriter: w, Status: http.StatusOK}
+		h.ServeHTTP(sw, r)
+		logger.Printf("%s %s %d (%s, %s)", r.Method, r.URL.RequestURI(),
+			sw.Status, time.Since(start).Round(time.Millisecond), r.RemoteAddr)
+	})
+}
+
+// statusWriter records the status code that is written to a ResponseWriter.
+type statusWriter struct {
+	http.ResponseWriter
+	Status int
+}
+
+func (sw *statusWriter) WriteHeader(status int) {
+	sw.Status = status
+	sw.ResponseWriter.WriteHeader(status)
+}
+
+// Flush implements http.Flusher, if the underlying ResponseWriter does.
+func (sw *statusWriter) Flush() {
+	if f, ok := sw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,243 @@
+// Package server serves data from Quest as JSON over HTTP, so that programs
+// that can't use package uwquest directly (such as web dashboards) can read
+// it.
+//
+// It serves the following endpoints, where {code} is a term code (see
+// uwquest.Term.Code):
+//
+//	GET /terms                   The student's terms.
+//	GET /terms/{code}/grades     The grades for a term.
+//	GET /terms/{code}/schedule   The course schedule for a term.
+//	GET /events                  A stream of events (see Broker), if
+//	                             Server.Events is set.
+//	GET /healthz                 Whether the server is running.
+//	GET /readyz                  Whether the server can reach Quest.
+//
+// Responses are cached for a while (see Server.TTL), and carry ETags, so that
+// clients that refresh often do not each cause a request to Quest.
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/stevenxie/uwquest"
+	ess "github.com/unixpickle/essentials"
+)
+
+// DefaultTTL is the time that a Server caches responses for by default.
+const DefaultTTL = 5 * time.Minute
+
+// A Quest fetches data from Quest. It is implemented by *uwquest.Client.
+type Quest interface {
+	Terms() ([]*uwquest.Term, error)
+	TermsWithSchedule() ([]*uwquest.Term, error)
+	Grades(termIndex int) (*uwquest.TermGrades, error)
+	Schedules(termIndex int) ([]*uwquest.CourseSchedule, error)
+}
+
+var _ Quest = (*uwquest.Client)(nil)
+
+// A Server is an http.Handler that serves data from Quest as JSON.
+//
+// A Server only makes one request to Quest at a time, since a uwquest.Client
+// (and the Quest session behind it) cannot be used concurrently. Cached
+// responses are served while a request is being made, and concurrent
+// requests for the same endpoint share a single request to Quest.
+type Server struct {
+	Quest Quest
+
+	// TTL is the time that responses are cached for, which defaults to
+	// DefaultTTL.
+	TTL time.Duration
+
+	// Events, if set, serves the /events stream.
+	Events *Broker
+
+	questMu sync.Mutex // serializes requests to Quest
+
+	mu    sync.Mutex // guards cache and calls
+	cache map[string]*entry
+	calls map[string]*call
+}
+
+// A call is a request to Quest that is in progress, which requests for the
+// same endpoint wait for.
+type call struct {
+	done chan struct{} // closed when the call is done
+	e    *entry
+	err  error
+}
+
+var _ http.Handler = (*Server)(nil)
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		w.Header().Set("Allow", "GET, HEAD")
+		writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	key := strings.Join(parts, "/") // so that "/terms/" is cached as "/terms"
+	switch {
+	case len(parts) == 1 && parts[0] == "healthz":
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	case len(parts) == 1 && parts[0] == "readyz":
+		s.serveReady(w)
+	case len(parts) == 1 && parts[0] == "events" && s.Events != nil:
+		s.Events.ServeHTTP(w, r)
+	case len(parts) == 1 && parts[0] == "terms":
+		s.serve(w, r, key, func() (interface{}, error) {
+			return s.Quest.Terms()
+		})
+	case len(parts) == 3 && parts[0] == "terms" && parts[2] == "grades":
+		s.serveTerm(w, r, key, parts[1], "terms", s.Quest.Terms,
+			func(term *uwquest.Term) (interface{}, error) {
+				return s.Quest.Grades(term.Index)
+			})
+	case len(parts) == 3 && parts[0] == "terms" && parts[2] == "schedule":
+		s.serveTerm(w, r, key, parts[1], scheduleTermsKey,
+			s.Quest.TermsWithSchedule,
+			func(term *uwquest.Term) (interface{}, error) {
+				return s.Quest.Schedules(term.Index)
+			})
+	default:
+		writeError(w, http.StatusNotFound, "not found")
+	}
+}
+
+// serveReady reports whether the server can fetch the student's terms, which
+// requires a working Quest session.
+func (s *Server) serveReady(w http.ResponseWriter) {
+	if _, err := s.get("terms", func() (interface{}, error) {
+		return s.Quest.Terms()
+	}); err != nil {
+		writeError(w, http.StatusServiceUnavailable, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ready"})
+}
+
+// scheduleTermsKey is the cache key for the terms returned by
+// TermsWithSchedule, which aren't served by any endpoint.
+const scheduleTermsKey = "schedule-terms"
+
+// serveTerm serves the result of fetch for the term with the given code,
+// looking up the term among those returned by terms, which are cached as
+// termsKey. Unknown codes are answered from the cached terms, rather than by
+// fetching them again.
+func (s *Server) serveTerm(w http.ResponseWriter, r *http.Request,
+	key, code, termsKey string, terms func() ([]*uwquest.Term, error),
+	fetch func(term *uwquest.Term) (interface{}, error)) {
+	e, err := s.get(termsKey, func() (interface{}, error) { return terms() })
+	if e == nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	for _, term := range e.Value.([]*uwquest.Term) {
+		if c, err := term.Code(); err == nil && c == code {
+			s.serve(w, r, key, func() (interface{}, error) { return fetch(term) })
+			return
+		}
+	}
+	writeError(w, http.StatusNotFound, fmt.Sprintf("no such term '%s'", code))
+}
+
+// serve writes the (possibly cached) JSON encoding of the result of fetch.
+// If fetch fails, but a response was cached for key before, that response is
+// written instead.
+func (s *Server) serve(w http.ResponseWriter, r *http.Request, key string,
+	fetch func() (interface{}, error)) {
+	e, err := s.get(key, fetch)
+	if e == nil {
+		writeError(w, http.StatusBadGateway, err.Error())
+		return
+	}
+	e.serve(w, r, s.ttl())
+}
+
+// get returns the cached response for key, or caches the result of fetch if
+// there is no fresh response cached. If fetch fails, get returns its error
+// along with the stale response for key, if there is one.
+func (s *Server) get(key string, fetch func() (interface{}, error)) (*entry,
+	error) {
+	s.mu.Lock()
+	stale := s.cache[key]
+	if stale != nil && time.Since(stale.Fetched) < s.ttl() {
+		s.mu.Unlock()
+		return stale, nil
+	}
+	if c, ok := s.calls[key]; ok {
+		s.mu.Unlock()
+		<-c.done
+		return c.e, c.err
+	}
+	c := &call{done: make(chan struct{})}
+	if s.calls == nil {
+		s.calls = make(map[string]*call)
+	}
+	s.calls[key] = c
+	s.mu.Unlock()
+
+	c.e, c.err = s.fetch(fetch)
+
+	s.mu.Lock()
+	delete(s.calls, key)
+	if c.err == nil {
+		if s.cache == nil {
+			s.cache = make(map[string]*entry)
+		}
+		s.cache[key] = c.e
+	} else {
+		c.e = stale
+	}
+	s.mu.Unlock()
+	close(c.done)
+	return c.e, c.err
+}
+
+// fetch calls fetch while no other requests are being made to Quest, and
+// returns a new entry for its result.
+func (s *Server) fetch(fetch func() (interface{}, error)) (*entry, error) {
+	s.questMu.Lock()
+	defer s.questMu.Unlock()
+
+	now := time.Now()
+	v, err := fetch()
+	if err != nil {
+		return nil, err
+	}
+	e, err := newEntry(v, now)
+	return e, ess.AddCtx("server: encoding response", err)
+}
+
+// Flush removes all cached responses, so that the next request for each
+// endpoint fetches it from Quest again.
+func (s *Server) Flush() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.cache = nil
+}
+
+func (s *Server) ttl() time.Duration {
+	if s.TTL <= 0 {
+		return DefaultTTL
+	}
+	return s.TTL
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, msg string) {
+	writeJSON(w, status, map[string]string{"error": msg})
+}
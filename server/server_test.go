@@ -0,0 +1,243 @@
+package server_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stevenxie/uwquest"
+	"github.com/stevenxie/uwquest/server"
+)
+
+// fakeQuest serves a fixed set of terms, and counts the requests made to it.
+type fakeQuest struct {
+	Calls int
+	Err   error
+}
+
+func (fq *fakeQuest) Terms() ([]*uwquest.Term, error) {
+	fq.Calls++
+	if fq.Err != nil {
+		return nil, fq.Err
+	}
+	return []*uwquest.Term{
+		{Index: 0, Name: "Fall 2018", Career: "Undergraduate"},
+		{Index: 1, Name: "Winter 2018", Career: "Undergraduate"},
+	}, nil
+}
+
+func (fq *fakeQuest) TermsWithSchedule() ([]*uwquest.Term, error) {
+	fq.Calls++
+	return []*uwquest.Term{{Index: 0, Name: "Fall 2018"}}, fq.Err
+}
+
+func (fq *fakeQuest) Grades(termIndex int) (*uwquest.TermGrades, error) {
+	fq.Calls++
+	return &uwquest.TermGrades{Courses: []*uwquest.CourseGrade{
+		{Index: termIndex, Name: "CS 135", Grade: "85"},
+	}}, fq.Err
+}
+
+func (fq *fakeQuest) Schedules(termIndex int) ([]*uwquest.CourseSchedule,
+	error) {
+	fq.Calls++
+	return []*uwquest.CourseSchedule{{Name: "CS 136"}}, fq.Err
+}
+
+func get(h http.Handler, path string,
+	header http.Header) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, path, nil)
+	for k, v := range header {
+		r.Header[k] = v
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	return w
+}
+
+func TestServer_Grades(t *testing.T) {
+	quest := new(fakeQuest)
+	s := &server.Server{Quest: quest}
+
+	// "1189" is the code for Fall 2018, which has index 0.
+	w := get(s, "/terms/1189/grades", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body)
+	}
+	var grades uwquest.TermGrades
+	if err := json.Unmarshal(w.Body.Bytes(), &grades); err != nil {
+		t.Fatal(err)
+	}
+	if len(grades.Courses) != 1 || grades.Courses[0].Index != 0 ||
+		grades.Courses[0].Grade != "85" {
+		t.Errorf("Unexpected grades: %s", w.Body)
+	}
+	if w.Header().Get("ETag") == "" {
+		t.Error("Expected an ETag.")
+	}
+
+	calls := quest.Calls
+	for i := 0; i < 3; i++ {
+		if w = get(s, "/terms/1111/grades", nil); w.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404 for an unknown term, got %d", w.Code)
+		}
+	}
+	if quest.Calls != calls {
+		t.Errorf("Expected unknown terms to be looked up in the cached terms, "+
+			"got %d requests to Quest", quest.Calls-calls)
+	}
+	if w = get(s, "/terms/1189/schedule", nil); w.Code != http.StatusOK {
+		t.Errorf("Expected status 200 for a schedule, got %d: %s", w.Code,
+			w.Body)
+	}
+	if w = get(s, "/nope", nil); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for an unknown path, got %d", w.Code)
+	}
+}
+
+func TestServer_Cache(t *testing.T) {
+	quest := new(fakeQuest)
+	s := &server.Server{Quest: quest, TTL: time.Hour}
+
+	w := get(s, "/terms", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body)
+	}
+	etag := w.Header().Get("ETag")
+
+	// Repeated requests are served from the cache.
+	w = get(s, "/terms", http.Header{"If-None-Match": {etag}})
+	if w.Code != http.StatusNotModified {
+		t.Errorf("Expected status 304, got %d", w.Code)
+	}
+	if w = get(s, "/terms", nil); w.Code != http.StatusOK ||
+		w.Header().Get("ETag") != etag {
+		t.Errorf("Expected the cached response, got %d (%s)", w.Code,
+			w.Header().Get("ETag"))
+	}
+	get(s, "/terms/", nil) // the same endpoint
+	if quest.Calls != 1 {
+		t.Errorf("Expected 1 request to Quest, got %d", quest.Calls)
+	}
+
+	s.Flush()
+	get(s, "/terms", nil)
+	if quest.Calls != 2 {
+		t.Errorf("Expected a request to Quest after flushing, got %d",
+			quest.Calls)
+	}
+}
+
+func TestServer_Stale(t *testing.T) {
+	quest := new(fakeQuest)
+	s := &server.Server{Quest: quest, TTL: time.Nanosecond}
+
+	w := get(s, "/terms", nil)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body)
+	}
+	etag := w.Header().Get("ETag")
+
+	// When Quest fails, the last response is served instead, but the server
+	// isn't ready.
+	quest.Err = errors.New("session expired")
+	if w = get(s, "/terms", nil); w.Code != http.StatusOK ||
+		w.Header().Get("ETag") != etag {
+		t.Errorf("Expected the stale response, got %d (%s)", w.Code,
+			w.Header().Get("ETag"))
+	}
+	if quest.Calls != 2 {
+		t.Errorf("Expected 2 requests to Quest, got %d", quest.Calls)
+	}
+	if w = get(s, "/readyz", nil); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected /readyz to fail, got %d", w.Code)
+	}
+}
+
+// blockingQuest is a fakeQuest whose Terms blocks until Release is closed.
+type blockingQuest struct {
+	fakeQuest
+	Started chan struct{}
+	Release chan struct{}
+	calls   int32
+}
+
+func (bq *blockingQuest) Terms() ([]*uwquest.Term, error) {
+	if atomic.AddInt32(&bq.calls, 1) == 1 {
+		close(bq.Started)
+	}
+	<-bq.Release
+	return []*uwquest.Term{{Index: 0, Name: "Fall 2018"}}, nil
+}
+
+func TestServer_Concurrent(t *testing.T) {
+	quest := &blockingQuest{
+		Started: make(chan struct{}),
+		Release: make(chan struct{}),
+	}
+	s := &server.Server{Quest: quest, TTL: time.Hour}
+
+	if w := get(s, "/terms/1189/schedule", nil); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body)
+	}
+
+	// Concurrent requests for the same endpoint share a request to Quest.
+	var wg sync.WaitGroup
+	codes := make([]int, 5)
+	for i := range codes {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			codes[i] = get(s, "/terms", nil).Code
+		}(i)
+	}
+	<-quest.Started
+
+	// Cached responses don't wait for requests to Quest.
+	done := make(chan int)
+	go func() { done <- get(s, "/terms/1189/schedule", nil).Code }()
+	select {
+	case code := <-done:
+		if code != http.StatusOK {
+			t.Errorf("Expected the cached schedule, got %d", code)
+		}
+	case <-time.After(time.Second):
+		t.Error("Expected the cached schedule not to wait for Quest")
+	}
+
+	close(quest.Release)
+	wg.Wait()
+	for i, code := range codes {
+		if code != http.StatusOK {
+			t.Errorf("Request %d: expected status 200, got %d", i, code)
+		}
+	}
+	if n := atomic.LoadInt32(&quest.calls); n != 1 {
+		t.Errorf("Expected 1 request to Quest, got %d", n)
+	}
+}
+
+func TestServer_Health(t *testing.T) {
+	quest := &fakeQuest{Err: errors.New("session expired")}
+	s := &server.Server{Quest: quest}
+
+	if w := get(s, "/healthz", nil); w.Code != http.StatusOK {
+		t.Errorf("Expected /healthz to succeed, got %d", w.Code)
+	}
+	if w := get(s, "/readyz", nil); w.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected /readyz to fail, got %d", w.Code)
+	}
+	if w := get(s, "/terms", nil); w.Code != http.StatusBadGateway {
+		t.Errorf("Expected status 502 when Quest fails, got %d", w.Code)
+	}
+
+	quest.Err = nil
+	if w := get(s, "/readyz", nil); w.Code != http.StatusOK {
+		t.Errorf("Expected /readyz to succeed, got %d: %s", w.Code, w.Body)
+	}
+}
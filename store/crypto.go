@@ -0,0 +1,74 @@
+package store
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"io"
+)
+
+// Key derivation parameters.
+const (
+	keyIterations = 100000
+	keyLength     = 32 // for AES-256
+	saltLength    = 16
+)
+
+// deriveKey derives an encryption key from a passphrase, using PBKDF2 with
+// HMAC-SHA256 (as described in RFC 8018).
+func deriveKey(passphrase string, salt []byte, iterations int) ([]byte,
+	error) {
+	return pbkdf2.Key(sha256.New, passphrase, salt, iterations, keyLength)
+}
+
+// randomBytes returns n cryptographically random bytes.
+func randomBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	_, err := io.ReadFull(rand.Reader, b)
+	return b, err
+}
+
+// sealer encrypts and decrypts data using AES-GCM. Sealed data is prefixed
+// with its nonce.
+type sealer struct {
+	aead cipher.AEAD
+}
+
+func newSealer(key []byte) (*sealer, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &sealer{aead: aead}, nil
+}
+
+func (s *sealer) Seal(plaintext []byte) ([]byte, error) {
+	nonce, err := randomBytes(s.aead.NonceSize())
+	if err != nil {
+		return nil, err
+	}
+	return s.aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+// errDecrypt is returned when data cannot be decrypted, either because the
+// key is wrong or because the data was modified.
+var errDecrypt = errors.New("could not decrypt data")
+
+func (s *sealer) Open(data []byte) ([]byte, error) {
+	size := s.aead.NonceSize()
+	if len(data) < size {
+		return nil, errDecrypt
+	}
+	plaintext, err := s.aead.Open(nil, data[:size], data[size:], nil)
+	if err != nil {
+		return nil, errDecrypt
+	}
+	return plaintext, nil
+}
@@ -0,0 +1,30 @@
+package store
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestDeriveKey(t *testing.T) {
+	// The PBKDF2-HMAC-SHA256 counterparts of the first RFC 6070 test cases
+	// (which are for PBKDF2-HMAC-SHA1), with the password "password" and the
+	// salt "salt".
+	for _, c := range []struct {
+		Iterations int
+		Expected   string
+	}{
+		{1, "120fb6cffcf8b32c43e7225256c4f837a86548c92ccc35480805987cb70be17b"},
+		{2, "ae4d0c95af6b46d32d0adff928f06dd02a303f8ef3c251dfd6e2d85a95474c43"},
+		{4096,
+			"c5e478d59288c841aa530db6845c4c8d962893a001ce4e11a4963873aa98134a"},
+	} {
+		key, err := deriveKey("password", []byte("salt"), c.Iterations)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := hex.EncodeToString(key); got != c.Expected {
+			t.Errorf("Iterations %d: expected key %s, got %s", c.Iterations,
+				c.Expected, got)
+		}
+	}
+}
@@ -0,0 +1,122 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/stevenxie/uwquest/diff"
+	"github.com/stevenxie/uwquest/notify"
+	ess "github.com/unixpickle/essentials"
+)
+
+// DefaultMaxEvents is the number of events that a Store keeps in its event
+// log by default.
+const DefaultMaxEvents = 1000
+
+// eventsFile is the name of the file that contains a store's event log.
+const eventsFile = "events.json"
+
+// A LoggedEvent is an event in an event log. Its ID is greater than the IDs
+// of the events logged before it.
+type LoggedEvent struct {
+	ID   int64     `json:"id"`
+	Time time.Time `json:"time"`
+	Type string    `json:"type"`
+	Text string    `json:"text"`
+
+	// Term and Course are the term and course that the event is about, if it
+	// is a diff.Change.
+	Term   string `json:"term,omitempty"`
+	Course string `json:"course,omitempty"`
+
+	Data json.RawMessage `json:"data"`
+}
+
+func (le *LoggedEvent) String() string {
+	return fmt.Sprintf("LoggedEvent{ID: %d, Time: %s, Type: %s, Text: %s}",
+		le.ID, le.Time.Format(time.RFC3339), le.Type, le.Text)
+}
+
+// NewLoggedEvent encodes e as a LoggedEvent with the given ID and time.
+func NewLoggedEvent(id int64, e notify.Event, t time.Time) (*LoggedEvent,
+	error) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return nil, ess.AddCtx("store: encoding event", err)
+	}
+	le := &LoggedEvent{
+		ID:   id,
+		Time: t,
+		Type: e.EventType(),
+		Text: e.String(),
+		Data: data,
+	}
+	if c, ok := e.(diff.Change); ok {
+		key := c.ChangeKey()
+		le.Term, le.Course = key.Term, key.Course
+	}
+	return le, nil
+}
+
+// eventLog is the contents of an event log file.
+type eventLog struct {
+	LastID int64          `json:"lastId"`
+	Events []*LoggedEvent `json:"events"` // from oldest to newest
+}
+
+// AppendEvent adds e to the store's event log, and returns it as it was
+// logged. Only the latest events are kept (see Options.MaxEvents).
+func (s *Store) AppendEvent(e notify.Event) (*LoggedEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	log, err := s.readEventLog()
+	if err != nil {
+		return nil, err
+	}
+	le, err := NewLoggedEvent(log.LastID+1, e, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	log.LastID = le.ID
+	log.Events = append(log.Events, le)
+	if len(log.Events) > s.maxEvents {
+		log.Events = log.Events[len(log.Events)-s.maxEvents:]
+	}
+	if err = s.writeFile(s.eventsPath(), log); err != nil {
+		return nil, ess.AddCtx("store: writing event log", err)
+	}
+	return le, nil
+}
+
+// EventsSince returns the logged events with IDs greater than id, from oldest
+// to newest. Events that are no longer kept are omitted.
+func (s *Store) EventsSince(id int64) ([]*LoggedEvent, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	log, err := s.readEventLog()
+	if err != nil {
+		return nil, err
+	}
+	for i, le := range log.Events {
+		if le.ID > id {
+			return log.Events[i:], nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *Store) readEventLog() (*eventLog, error) {
+	log := new(eventLog)
+	if err := s.readFile(s.eventsPath(), log); err != nil {
+		return nil, ess.AddCtx("store: reading event log", err)
+	}
+	return log, nil
+}
+
+func (s *Store) eventsPath() string {
+	return filepath.Join(s.dir, eventsFile)
+}
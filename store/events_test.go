@@ -0,0 +1,52 @@
+package store_test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stevenxie/uwquest/diff"
+	"github.com/stevenxie/uwquest/store"
+)
+
+func TestStore_Events(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	s, err := store.Open(dir, &store.Options{Passphrase: "hunter2",
+		MaxEvents: 2})
+	if err != nil {
+		t.Fatalf("Error while opening store: %v", err)
+	}
+	for _, grade := range []string{"80", "85", "90"} {
+		if _, err = s.AppendEvent(&diff.GradePosted{
+			Key:   diff.Key{Term: "Fall 2018", Course: "CS 135"},
+			Grade: grade,
+		}); err != nil {
+			t.Fatalf("Error while logging event: %v", err)
+		}
+	}
+
+	// Only the last two events are kept, and they survive reopening the
+	// store.
+	if s, err = store.Open(dir, &store.Options{
+		Passphrase: "hunter2",
+	}); err != nil {
+		t.Fatalf("Error while reopening store: %v", err)
+	}
+	events, err := s.EventsSince(0)
+	if err != nil {
+		t.Fatalf("Error while reading events: %v", err)
+	}
+	if len(events) != 2 || events[0].ID != 2 || events[1].ID != 3 {
+		t.Fatalf("Expected events 2 and 3, got: %v", events)
+	}
+	if e := events[1]; e.Type != "grade_posted" || e.Term != "Fall 2018" ||
+		e.Course != "CS 135" {
+		t.Errorf("Unexpected event: %+v", e)
+	}
+
+	if events, err = s.EventsSince(3); err != nil || len(events) != 0 {
+		t.Errorf("Expected no events after the last one, got: %v, %v", events,
+			err)
+	}
+}
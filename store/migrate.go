@@ -0,0 +1,76 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/stevenxie/uwquest"
+	ess "github.com/unixpickle/essentials"
+)
+
+// CurrentVersion is the version of the store layout that this package
+// writes.
+const CurrentVersion = 1
+
+// migrations upgrade stores from one version to the next: migrations[v]
+// upgrades a store from version v to version v+1.
+var migrations = []func(s *Store) error{
+	0: migrateLegacyGrades,
+}
+
+// migrate upgrades the store described by m to CurrentVersion, saving its
+// metadata after each step.
+func (s *Store) migrate(m *meta) error {
+	for m.Version < CurrentVersion {
+		if err := migrations[m.Version](s); err != nil {
+			return ess.AddCtx(fmt.Sprintf("store: migrating from version %d",
+				m.Version), err)
+		}
+		m.Version++
+		if err := s.writeMeta(m); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// legacyGradesFile is the name of the grades file written by watch.FileStore,
+// which stores predating versioning may contain.
+const legacyGradesFile = "grades.json"
+
+// migrateLegacyGrades imports the grades in a legacy grades file as grades
+// records, and renames the file so that it isn't imported again. If the store
+// is encrypted, the file is deleted instead, so that no plaintext grades are
+// left behind.
+func migrateLegacyGrades(s *Store) error {
+	path := filepath.Join(s.dir, legacyGradesFile)
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return ess.AddCtx("reading legacy grades", err)
+	}
+
+	var terms map[string][]*uwquest.CourseGrade
+	if err = json.Unmarshal(data, &terms); err != nil {
+		return ess.AddCtx("decoding legacy grades", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	for term, grades := range terms {
+		if err = s.Save(GradesKind, term, grades, info.ModTime()); err != nil {
+			return err
+		}
+	}
+	if s.sealer != nil {
+		return os.Remove(path)
+	}
+	return os.Rename(path, path+".migrated-"+time.Now().Format("20060102"))
+}
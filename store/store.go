@@ -0,0 +1,471 @@
+// Package store persists data fetched from Quest in a local directory of
+// versioned JSON files, so that watchers and reports don't need to fetch data
+// from Quest that they already have.
+//
+// Each record (i.e. the grades for a term) keeps a history of its values,
+// along with when each value was fetched. Stores also keep a log of recent
+// events (see AppendEvent), and can optionally be encrypted at rest using a
+// passphrase.
+package store
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/stevenxie/uwquest"
+	ess "github.com/unixpickle/essentials"
+)
+
+// The kinds of records kept in a Store.
+const (
+	TermsKind     = "terms"
+	GradesKind    = "grades"
+	SchedulesKind = "schedules"
+)
+
+// termsKey is the key of the only terms record.
+const termsKey = "all"
+
+// metaFile is the name of the file that describes a store.
+const metaFile = "meta.json"
+
+// Errors returned by Open.
+var (
+	ErrEncrypted = errors.New("store: store is encrypted, but no " +
+		"passphrase was given")
+	ErrNotEncrypted  = errors.New("store: store is not encrypted")
+	ErrBadPassphrase = errors.New("store: incorrect passphrase")
+	ErrNewerVersion  = errors.New("store: store was created by a newer " +
+		"version of this package")
+)
+
+// Options configures a Store.
+type Options struct {
+	// Passphrase, if set, is used to encrypt a new store, or to decrypt an
+	// existing one. A store's encryption can't be changed after it is created.
+	Passphrase string
+
+	// MaxHistory is the number of values to keep for each record; older values
+	// are discarded. If zero, every value is kept.
+	MaxHistory int
+
+	// MaxEvents is the number of events to keep in the event log, which
+	// defaults to DefaultMaxEvents.
+	MaxEvents int
+}
+
+// A Store is a directory of records fetched from Quest. It is safe for
+// concurrent use, but not by multiple processes at once.
+type Store struct {
+	dir        string
+	maxHistory int
+	maxEvents  int
+	sealer     *sealer // nil if the store is not encrypted
+
+	mu sync.Mutex
+}
+
+// meta describes a store.
+type meta struct {
+	Version    int               `json:"version"`
+	Encryption *encryptionParams `json:"encryption,omitempty"`
+}
+
+// encryptionParams are the parameters used to encrypt a store.
+type encryptionParams struct {
+	Salt       string `json:"salt"` // base64-encoded
+	Iterations int    `json:"iterations"`
+
+	// Check is a known value encrypted with the store's key, which is used to
+	// check passphrases.
+	Check string `json:"check"` // base64-encoded
+}
+
+// checkPlaintext is the value that is encrypted in encryptionParams.Check.
+var checkPlaintext = []byte("uwquest store")
+
+// Open opens the store in dir, creating it if it doesn't exist, and migrates
+// it to the current version if needed. opts may be nil.
+func Open(dir string, opts *Options) (*Store, error) {
+	if opts == nil {
+		opts = new(Options)
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, ess.AddCtx("store: creating directory", err)
+	}
+	s := &Store{
+		dir:        dir,
+		maxHistory: opts.MaxHistory,
+		maxEvents:  opts.MaxEvents,
+	}
+	if s.maxEvents <= 0 {
+		s.maxEvents = DefaultMaxEvents
+	}
+
+	m, err := s.readMeta()
+	if err != nil {
+		return nil, err
+	}
+	if m == nil {
+		// The store is new, or predates versioning.
+		if m, err = newMeta(opts.Passphrase); err != nil {
+			return nil, err
+		}
+	}
+	if m.Version > CurrentVersion {
+		return nil, ErrNewerVersion
+	}
+	if err = s.unlock(m.Encryption, opts.Passphrase); err != nil {
+		return nil, err
+	}
+
+	if m.Version < CurrentVersion {
+		if err = s.migrate(m); err != nil {
+			return nil, err
+		}
+	}
+	return s, nil
+}
+
+// newMeta returns the meta for a new store, encrypted using passphrase if it
+// is set. The meta's version is 0, so that any legacy files are migrated.
+func newMeta(passphrase string) (*meta, error) {
+	m := new(meta)
+	if passphrase == "" {
+		return m, nil
+	}
+
+	salt, err := randomBytes(saltLength)
+	if err != nil {
+		return nil, ess.AddCtx("store: generating salt", err)
+	}
+	key, err := deriveKey(passphrase, salt, keyIterations)
+	if err != nil {
+		return nil, ess.AddCtx("store: deriving key", err)
+	}
+	sealer, err := newSealer(key)
+	if err != nil {
+		return nil, ess.AddCtx("store: creating cipher", err)
+	}
+	check, err := sealer.Seal(checkPlaintext)
+	if err != nil {
+		return nil, ess.AddCtx("store: encrypting passphrase check", err)
+	}
+	m.Encryption = &encryptionParams{
+		Salt:       base64.StdEncoding.EncodeToString(salt),
+		Iterations: keyIterations,
+		Check:      base64.StdEncoding.EncodeToString(check),
+	}
+	return m, nil
+}
+
+// unlock sets up s's encryption using params and passphrase.
+func (s *Store) unlock(params *encryptionParams, passphrase string) error {
+	switch {
+	case params == nil && passphrase == "":
+		return nil
+	case params == nil:
+		return ErrNotEncrypted
+	case passphrase == "":
+		return ErrEncrypted
+	}
+
+	salt, err := base64.StdEncoding.DecodeString(params.Salt)
+	if err != nil {
+		return ess.AddCtx("store: decoding salt", err)
+	}
+	check, err := base64.StdEncoding.DecodeString(params.Check)
+	if err != nil {
+		return ess.AddCtx("store: decoding passphrase check", err)
+	}
+	key, err := deriveKey(passphrase, salt, params.Iterations)
+	if err != nil {
+		return ess.AddCtx("store: deriving key", err)
+	}
+	sealer, err := newSealer(key)
+	if err != nil {
+		return ess.AddCtx("store: creating cipher", err)
+	}
+	if plaintext, err := sealer.Open(check); err != nil ||
+		!bytes.Equal(plaintext, checkPlaintext) {
+		return ErrBadPassphrase
+	}
+	s.sealer = sealer
+	return nil
+}
+
+func (s *Store) readMeta() (*meta, error) {
+	data, err := ioutil.ReadFile(filepath.Join(s.dir, metaFile))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, ess.AddCtx("store: reading metadata", err)
+	}
+	m := new(meta)
+	if err = json.Unmarshal(data, m); err != nil {
+		return nil, ess.AddCtx("store: decoding metadata", err)
+	}
+	return m, nil
+}
+
+func (s *Store) writeMeta(m *meta) error {
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return ess.AddCtx("store: encoding metadata", err)
+	}
+	return ess.AddCtx("store: writing metadata",
+		writeFile(filepath.Join(s.dir, metaFile), data))
+}
+
+// A Snapshot is a value of a record, as it was fetched from Quest.
+type Snapshot struct {
+	// FetchedAt is when the value was first fetched, and CheckedAt is when it
+	// was last fetched (and found to be unchanged).
+	FetchedAt time.Time       `json:"fetchedAt"`
+	CheckedAt time.Time       `json:"checkedAt"`
+	Data      json.RawMessage `json:"data"`
+}
+
+// Decode decodes the snapshot's value into v.
+func (s *Snapshot) Decode(v interface{}) error {
+	return json.Unmarshal(s.Data, v)
+}
+
+func (s *Snapshot) String() string {
+	return fmt.Sprintf("Snapshot{FetchedAt: %s, CheckedAt: %s, Data: %s}",
+		s.FetchedAt.Format(time.RFC3339), s.CheckedAt.Format(time.RFC3339),
+		s.Data)
+}
+
+// record is the contents of a record file.
+type record struct {
+	Snapshots []*Snapshot `json:"snapshots"` // from oldest to newest
+}
+
+// Save saves v as the latest value of the record identified by kind and key,
+// fetched at time t. If v is unchanged from the latest value, only that
+// value's CheckedAt is updated.
+func (s *Store) Save(kind, key string, v interface{}, t time.Time) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return ess.AddCtx("store: encoding record", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, err := s.readRecord(kind, key)
+	if err != nil {
+		return err
+	}
+	n := len(rec.Snapshots)
+	if n > 0 && jsonEqual(rec.Snapshots[n-1].Data, data) {
+		rec.Snapshots[n-1].CheckedAt = t
+	} else {
+		rec.Snapshots = append(rec.Snapshots, &Snapshot{
+			FetchedAt: t,
+			CheckedAt: t,
+			Data:      data,
+		})
+	}
+	if s.maxHistory > 0 && len(rec.Snapshots) > s.maxHistory {
+		rec.Snapshots = rec.Snapshots[len(rec.Snapshots)-s.maxHistory:]
+	}
+	return s.writeRecord(kind, key, rec)
+}
+
+// jsonEqual reports whether a and b are the same JSON, ignoring whitespace
+// (since records are written indented).
+func jsonEqual(a, b []byte) bool {
+	var ca, cb bytes.Buffer
+	if json.Compact(&ca, a) != nil || json.Compact(&cb, b) != nil {
+		return false
+	}
+	return bytes.Equal(ca.Bytes(), cb.Bytes())
+}
+
+// Latest returns the latest snapshot of a record, or nil if the record has
+// never been saved.
+func (s *Store) Latest(kind, key string) (*Snapshot, error) {
+	history, err := s.History(kind, key)
+	if err != nil || len(history) == 0 {
+		return nil, err
+	}
+	return history[len(history)-1], nil
+}
+
+// History returns every snapshot of a record, from oldest to newest.
+func (s *Store) History(kind, key string) ([]*Snapshot, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, err := s.readRecord(kind, key)
+	if err != nil {
+		return nil, err
+	}
+	return rec.Snapshots, nil
+}
+
+// Keys returns the keys of the records of a particular kind.
+func (s *Store) Keys(kind string) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	names, err := filepath.Glob(filepath.Join(s.dir, kind, "*.json"))
+	if err != nil {
+		return nil, ess.AddCtx("store: listing records", err)
+	}
+	keys := make([]string, 0, len(names))
+	for _, name := range names {
+		base := filepath.Base(name)
+		key, err := url.PathUnescape(base[:len(base)-len(".json")])
+		if err != nil {
+			return nil, ess.AddCtx(fmt.Sprintf("store: decoding key of '%s'",
+				name), err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// recordPath returns the path of the file that contains a record. Keys are
+// escaped, so that they can be any string.
+func (s *Store) recordPath(kind, key string) string {
+	return filepath.Join(s.dir, kind, url.PathEscape(key)+".json")
+}
+
+func (s *Store) readRecord(kind, key string) (*record, error) {
+	rec := new(record)
+	if err := s.readFile(s.recordPath(kind, key), rec); err != nil {
+		return nil, ess.AddCtx("store: reading record", err)
+	}
+	return rec, nil
+}
+
+func (s *Store) writeRecord(kind, key string, rec *record) error {
+	return ess.AddCtx("store: writing record",
+		s.writeFile(s.recordPath(kind, key), rec))
+}
+
+// readFile decodes the (possibly encrypted) JSON file at path into v. It
+// leaves v unchanged if the file does not exist.
+func (s *Store) readFile(path string, v interface{}) error {
+	data, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if s.sealer != nil {
+		if data, err = s.sealer.Open(data); err != nil {
+			return ess.AddCtx("decrypting", err)
+		}
+	}
+	return ess.AddCtx("decoding", json.Unmarshal(data, v))
+}
+
+// writeFile encodes v as JSON, encrypts it if the store is encrypted, and
+// writes it to the file at path.
+func (s *Store) writeFile(path string, v interface{}) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return ess.AddCtx("encoding", err)
+	}
+	if s.sealer != nil {
+		if data, err = s.sealer.Seal(data); err != nil {
+			return ess.AddCtx("encrypting", err)
+		}
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return ess.AddCtx("creating directory", err)
+	}
+	return writeFile(path, data)
+}
+
+// writeFile writes data to the file at path by writing it to a temporary file
+// first, so that the file is never left partially written.
+func writeFile(path string, data []byte) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), ".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// SaveTerms saves the terms that a student has been enrolled for.
+func (s *Store) SaveTerms(terms []*uwquest.Term) error {
+	return s.Save(TermsKind, termsKey, terms, time.Now())
+}
+
+// LatestTerms returns the terms that were saved last, along with when they
+// were fetched. It returns nil if no terms have been saved.
+func (s *Store) LatestTerms() ([]*uwquest.Term, time.Time, error) {
+	var terms []*uwquest.Term
+	t, err := s.latest(TermsKind, termsKey, &terms)
+	return terms, t, err
+}
+
+// SaveGrades saves the grades for a term (i.e. "Fall 2018").
+func (s *Store) SaveGrades(term string, grades []*uwquest.CourseGrade) error {
+	if grades == nil {
+		grades = []*uwquest.CourseGrade{} // distinguish from unsaved terms
+	}
+	return s.Save(GradesKind, term, grades, time.Now())
+}
+
+// LatestGrades returns the grades that were saved last for a term, or nil if
+// none have been saved. Together with SaveGrades, it implements
+// watch.GradeStore.
+func (s *Store) LatestGrades(term string) ([]*uwquest.CourseGrade, error) {
+	var grades []*uwquest.CourseGrade
+	_, err := s.latest(GradesKind, term, &grades)
+	return grades, err
+}
+
+// SaveSchedules saves the course schedules for a term.
+func (s *Store) SaveSchedules(term string,
+	schedules []*uwquest.CourseSchedule) error {
+	return s.Save(SchedulesKind, term, schedules, time.Now())
+}
+
+// LatestSchedules returns the course schedules that were saved last for a
+// term, along with when they were fetched. It returns nil if none have been
+// saved.
+func (s *Store) LatestSchedules(term string) ([]*uwquest.CourseSchedule,
+	time.Time, error) {
+	var schedules []*uwquest.CourseSchedule
+	t, err := s.latest(SchedulesKind, term, &schedules)
+	return schedules, t, err
+}
+
+// latest decodes the latest snapshot of a record into v, and returns when it
+// was fetched (or the zero time, if there is no snapshot).
+func (s *Store) latest(kind, key string, v interface{}) (time.Time, error) {
+	snap, err := s.Latest(kind, key)
+	if err != nil || snap == nil {
+		return time.Time{}, err
+	}
+	if err = snap.Decode(v); err != nil {
+		return time.Time{}, ess.AddCtx("store: decoding record", err)
+	}
+	return snap.FetchedAt, nil
+}
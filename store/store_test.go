@@ -0,0 +1,187 @@
+package store_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stevenxie/uwquest"
+	"github.com/stevenxie/uwquest/store"
+	"github.com/stevenxie/uwquest/watch"
+)
+
+var (
+	_ watch.GradeStore    = (*store.Store)(nil)
+	_ watch.ScheduleStore = (*store.Store)(nil)
+)
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "store")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestStore_History(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	s, err := store.Open(dir, &store.Options{MaxHistory: 3})
+	if err != nil {
+		t.Fatalf("Error while opening store: %v", err)
+	}
+
+	start := time.Date(2018, 12, 1, 0, 0, 0, 0, time.UTC)
+	for i, grade := range []string{"", "", "", "85", "90"} {
+		grades := []*uwquest.CourseGrade{{Name: "CS 135", Grade: grade}}
+		if err = s.Save(store.GradesKind, "Fall 2018", grades,
+			start.Add(time.Duration(i)*time.Hour)); err != nil {
+			t.Fatalf("Error while saving grades: %v", err)
+		}
+	}
+
+	// Unchanged values only update CheckedAt, and only the last three values
+	// are kept.
+	history, err := s.History(store.GradesKind, "Fall 2018")
+	if err != nil {
+		t.Fatalf("Error while reading history: %v", err)
+	}
+	if len(history) != 3 {
+		t.Fatalf("Expected 3 snapshots, got: %v", history)
+	}
+	if !history[0].FetchedAt.Equal(start) ||
+		!history[0].CheckedAt.Equal(start.Add(2*time.Hour)) {
+		t.Errorf("Unexpected snapshot: %v", history[0])
+	}
+
+	grades, err := s.LatestGrades("Fall 2018")
+	if err != nil || len(grades) != 1 || grades[0].Grade != "90" {
+		t.Errorf("Unexpected latest grades: %v, %v", grades, err)
+	}
+	if grades, err = s.LatestGrades("Winter 2019"); err != nil ||
+		grades != nil {
+		t.Errorf("Expected no grades for an unsaved term, got: %v, %v", grades,
+			err)
+	}
+
+	keys, err := s.Keys(store.GradesKind)
+	if err != nil || len(keys) != 1 || keys[0] != "Fall 2018" {
+		t.Errorf("Unexpected keys: %v, %v", keys, err)
+	}
+}
+
+func TestStore_Encryption(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	s, err := store.Open(dir, &store.Options{Passphrase: "hunter2"})
+	if err != nil {
+		t.Fatalf("Error while creating store: %v", err)
+	}
+	terms := []*uwquest.Term{{Name: "Fall 2018", Career: "Undergraduate"}}
+	if err = s.SaveTerms(terms); err != nil {
+		t.Fatalf("Error while saving terms: %v", err)
+	}
+
+	// The record should not contain any plaintext.
+	data, err := ioutil.ReadFile(filepath.Join(dir, store.TermsKind,
+		"all.json"))
+	if err != nil {
+		t.Fatalf("Error while reading record: %v", err)
+	}
+	if contains(data, "Undergraduate") {
+		t.Error("Expected record to be encrypted.")
+	}
+
+	if _, err = store.Open(dir, nil); err != store.ErrEncrypted {
+		t.Errorf("Expected ErrEncrypted, got: %v", err)
+	}
+	if _, err = store.Open(dir, &store.Options{
+		Passphrase: "hunter3",
+	}); err != store.ErrBadPassphrase {
+		t.Errorf("Expected ErrBadPassphrase, got: %v", err)
+	}
+
+	s, err = store.Open(dir, &store.Options{Passphrase: "hunter2"})
+	if err != nil {
+		t.Fatalf("Error while reopening store: %v", err)
+	}
+	saved, _, err := s.LatestTerms()
+	if err != nil || len(saved) != 1 || saved[0].Career != "Undergraduate" {
+		t.Errorf("Unexpected terms: %v, %v", saved, err)
+	}
+}
+
+func TestOpen_MigratesLegacyGrades(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	legacy := watch.NewFileStore(filepath.Join(dir, "grades.json"))
+	grades := []*uwquest.CourseGrade{{Name: "CS 135", Grade: "85"}}
+	if err := legacy.SaveGrades("Fall 2018", grades); err != nil {
+		t.Fatalf("Error while saving legacy grades: %v", err)
+	}
+
+	s, err := store.Open(dir, nil)
+	if err != nil {
+		t.Fatalf("Error while opening store: %v", err)
+	}
+	migrated, err := s.LatestGrades("Fall 2018")
+	if err != nil || len(migrated) != 1 || migrated[0].Grade != "85" {
+		t.Errorf("Unexpected migrated grades: %v, %v", migrated, err)
+	}
+	if _, err = os.Stat(filepath.Join(dir, "grades.json")); !os.IsNotExist(err) {
+		t.Error("Expected legacy grades file to be moved aside.")
+	}
+}
+
+func TestOpen_MigratesLegacyGradesEncrypted(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	legacy := watch.NewFileStore(filepath.Join(dir, "grades.json"))
+	grades := []*uwquest.CourseGrade{{Name: "CS 135", Grade: "85"}}
+	if err := legacy.SaveGrades("Fall 2018", grades); err != nil {
+		t.Fatalf("Error while saving legacy grades: %v", err)
+	}
+
+	s, err := store.Open(dir, &store.Options{Passphrase: "hunter2"})
+	if err != nil {
+		t.Fatalf("Error while opening store: %v", err)
+	}
+	migrated, err := s.LatestGrades("Fall 2018")
+	if err != nil || len(migrated) != 1 || migrated[0].Grade != "85" {
+		t.Errorf("Unexpected migrated grades: %v, %v", migrated, err)
+	}
+
+	// No plaintext grades should be left anywhere in the store.
+	err = filepath.Walk(dir, func(path string, info os.FileInfo,
+		err error) error {
+		if err != nil || info.IsDir() {
+			return err
+		}
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if contains(data, "CS 135") {
+			t.Errorf("Found plaintext grades in %s", path)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func contains(data []byte, s string) bool {
+	for i := 0; i+len(s) <= len(data); i++ {
+		if string(data[i:i+len(s)]) == s {
+			return true
+		}
+	}
+	return false
+}
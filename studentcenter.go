@@ -0,0 +1,103 @@
+package uwquest
+
+import (
+	"fmt"
+	"strings"
+
+	gq "github.com/PuerkitoBio/goquery"
+	ess "github.com/unixpickle/essentials"
+)
+
+// followStudentCenterLink fetches the Student Center, and follows the link
+// identified by the PeopleSoft action linkAction (i.e. a box's "details" link).
+//
+// It returns nil if the Student Center does not show the link, which is the
+// case when the box that contains it is empty.
+func (c *Client) followStudentCenterLink(linkAction string) (*gq.Document,
+	error) {
+	page, err := c.fetchPage(StudentCenterURL)
+	if err != nil {
+		return nil, ess.AddCtx("fetching Student Center", err)
+	}
+	if page.Find("#"+escapeID(linkAction)).Length() == 0 {
+		return nil, nil
+	}
+
+	page, err = c.submitPage(StudentCenterURL, page, linkAction, nil)
+	return page, ess.AddCtx("following Student Center link", err)
+}
+
+// visitDetailPages visits the detail page linked from each row of a list page
+// (which was fetched from listURL), calling visit with each row's index and
+// detail page. Each page is submitted to its own form's URL (see pageURL).
+//
+// Row links are identified by linkID (i.e. "SRVC_IND_CD_TBL_DESCR"), and
+// returnAction is the PeopleSoft action that returns from a detail page to
+// the list page.
+func (c *Client) visitDetailPages(listURL string, list *gq.Document, linkID,
+	returnAction string, visit func(index int, detail *gq.Document) error) error {
+	var indices []int
+	list.Find(fmt.Sprintf(`[id^="%s$"]`, linkID)).Each(
+		func(_ int, link *gq.Selection) {
+			if index, err := selectionIndex(link); err == nil {
+				indices = append(indices, index)
+			}
+		})
+
+	for _, index := range indices {
+		listURL = pageURL(list, listURL)
+		detail, err := c.submitPage(listURL, list,
+			fmt.Sprintf("%s$%d", linkID, index), nil)
+		if err != nil {
+			return ess.AddCtx(fmt.Sprintf("opening detail page %d", index), err)
+		}
+		if err = visit(index, detail); err != nil {
+			return ess.AddCtx(fmt.Sprintf("detail page %d", index), err)
+		}
+		if list, err = c.submitPage(pageURL(detail, listURL), detail,
+			returnAction, nil); err != nil {
+			return ess.AddCtx(fmt.Sprintf("returning from detail page %d", index),
+				err)
+		}
+	}
+	return nil
+}
+
+// escapeID escapes the '$' characters in a PeopleSoft element ID, so that it
+// can be used in a CSS selector.
+func escapeID(id string) string {
+	return strings.Replace(id, "$", `\$`, -1)
+}
+
+// fieldText returns the trimmed text of the element with the given ID, or ""
+// if there is no such element.
+func fieldText(sel *gq.Selection, id string) string {
+	const nbsp = "\u00a0"
+	text := sel.Find("#" + escapeID(id)).Text()
+	return strings.TrimSpace(strings.Replace(text, nbsp, " ", -1))
+}
+
+// fieldValue returns the value of the form field with the given ID: the value
+// of an input or of a select's chosen option, "Y" or "N" for a checkbox, or
+// the text of any other element.
+func fieldValue(sel *gq.Selection, id string) string {
+	field := sel.Find("#" + escapeID(id))
+	switch gq.NodeName(field) {
+	case "input":
+		if typ, _ := field.Attr("type"); typ == "checkbox" {
+			if _, checked := field.Attr("checked"); checked {
+				return "Y"
+			}
+			return "N"
+		}
+		value, _ := field.Attr("value")
+		return strings.TrimSpace(value)
+	case "select":
+		value, _ := field.Find("option[selected]").Attr("value")
+		return strings.TrimSpace(value)
+	case "textarea":
+		return strings.TrimSpace(field.Text())
+	default:
+		return fieldText(sel, id)
+	}
+}
@@ -0,0 +1,194 @@
+package uwquest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	gq "github.com/PuerkitoBio/goquery"
+	ess "github.com/unixpickle/essentials"
+)
+
+// A TaxReceipt is a tuition tax receipt (i.e. a T2202) that is available for
+// a particular year.
+type TaxReceipt struct {
+	Index int
+	Year  int
+	Form  string // i.e. "T2202"
+}
+
+func (tr *TaxReceipt) String() string {
+	return fmt.Sprintf("TaxReceipt{Index: %d, Year: %d, Form: %s}", tr.Index,
+		tr.Year, tr.Form)
+}
+
+// A Document is a file downloaded from Quest. Its Body must be closed once it
+// has been read.
+type Document struct {
+	ContentType string
+	Body        io.ReadCloser
+}
+
+// Report polling parameters, used while waiting for Quest to generate a
+// report.
+const (
+	reportPollInterval = 2 * time.Second
+	reportPollAttempts = 30
+)
+
+// ErrReportTimeout is returned when Quest takes too long to generate a report.
+var ErrReportTimeout = errors.New("uwquest: timed out waiting for report")
+
+// ErrNoTaxReceipt is returned by TaxReceiptDocument when there is no tax
+// receipt for the requested year.
+var ErrNoTaxReceipt = errors.New("uwquest: no tax receipt for that year")
+
+// TaxReceipts fetches the tax receipts that are available to view.
+func (c *Client) TaxReceipts() ([]*TaxReceipt, error) {
+	page, err := c.fetchPage(TaxReceiptsURL)
+	if err != nil {
+		return nil, ess.AddCtx("uwquest: fetching tax receipts page", err)
+	}
+	receipts, err := parseTaxReceipts(page.Selection)
+	return receipts, ess.AddCtx("uwquest: parsing tax receipts", err)
+}
+
+// TaxReceiptDocument fetches the tax receipt for a particular year.
+//
+// Quest generates the receipt as a report on request, so this may take a
+// while; it returns ErrReportTimeout if the report is not ready in time, or
+// ctx's error if ctx is done while waiting for it.
+func (c *Client) TaxReceiptDocument(ctx context.Context, year int) (
+	*Document, error) {
+	page, err := c.fetchPage(TaxReceiptsURL)
+	if err != nil {
+		return nil, ess.AddCtx("uwquest: fetching tax receipts page", err)
+	}
+	receipts, err := parseTaxReceipts(page.Selection)
+	if err != nil {
+		return nil, ess.AddCtx("uwquest: parsing tax receipts", err)
+	}
+
+	var receipt *TaxReceipt
+	for _, r := range receipts {
+		if r.Year == year {
+			receipt = r
+			break
+		}
+	}
+	if receipt == nil {
+		return nil, ErrNoTaxReceipt
+	}
+
+	// Request the report, and poll until it is ready.
+	action := fmt.Sprintf("DERIVED_SSF_MSG_SSF_VIEW_PB$%d", receipt.Index)
+	if page, err = c.submitPage(TaxReceiptsURL, page, action, nil); err != nil {
+		return nil, ess.AddCtx("uwquest: requesting tax receipt", err)
+	}
+
+	var reportURL string
+	for attempt := 0; ; attempt++ {
+		if reportURL, err = findReportURL(page.Selection); err != nil {
+			return nil, ess.AddCtx("uwquest: checking report status", err)
+		}
+		if reportURL != "" {
+			break
+		}
+		if attempt == reportPollAttempts {
+			return nil, ErrReportTimeout
+		}
+
+		timer := time.NewTimer(reportPollInterval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		if page, err = c.submitPage(TaxReceiptsURL, page,
+			"DERIVED_SSF_MSG_SSF_REFRESH_PB", nil); err != nil {
+			return nil, ess.AddCtx("uwquest: refreshing report status", err)
+		}
+	}
+
+	res, err := c.Session.Get(reportURL)
+	if err != nil {
+		return nil, ess.AddCtx("uwquest: fetching tax receipt", err)
+	}
+	if res.StatusCode != http.StatusOK {
+		res.Body.Close()
+		return nil, fmt.Errorf("uwquest: got non-200 status code while fetching "+
+			"tax receipt: got code %d", res.StatusCode)
+	}
+	return &Document{
+		ContentType: res.Header.Get("Content-Type"),
+		Body:        res.Body,
+	}, nil
+}
+
+func parseTaxReceipts(sel *gq.Selection) ([]*TaxReceipt, error) {
+	var (
+		receipts []*TaxReceipt
+		err      error
+	)
+	sel.Find(`[id^="SSF_T2202A_VW_TAX_YEAR$"]`).EachWithBreak(
+		func(i int, year *gq.Selection) bool {
+			r := new(TaxReceipt)
+			if r.Index, err = selectionIndex(year); err != nil {
+				return false
+			}
+			text := strings.TrimSpace(year.Text())
+			if r.Year, err = strconv.Atoi(text); err != nil {
+				ess.AddCtxTo(fmt.Sprintf("row %d: parsing tax year", i), &err)
+				return false
+			}
+			r.Form = fieldText(sel, fmt.Sprintf("SSF_T2202A_VW_DESCR$%d",
+				r.Index))
+
+			receipts = append(receipts, r)
+			return true
+		})
+	return receipts, err
+}
+
+// reportURLRegexp matches the script that PeopleSoft uses to open a generated
+// report in a new window.
+var reportURLRegexp = regexp.MustCompile(`window\.open\('([^']+)'`)
+
+// findReportURL finds the URL of a generated report on a report status page.
+// It returns an empty string if the report is still being generated.
+func findReportURL(sel *gq.Selection) (string, error) {
+	status := fieldText(sel, "DERIVED_SSF_MSG_SSF_RPT_STATUS")
+	if strings.Contains(status, "Error") ||
+		strings.Contains(status, "No Success") {
+		return "", fmt.Errorf("report failed with status '%s'", status)
+	}
+
+	var href string
+	if link := sel.Find(`a[href*="/psreports/"]`); link.Length() > 0 {
+		href, _ = link.First().Attr("href")
+	} else if match := reportURLRegexp.FindStringSubmatch(
+		sel.Find("script").Text()); match != nil {
+		href = match[1]
+	}
+	if href == "" {
+		return "", nil
+	}
+
+	base, err := url.Parse(BaseURL)
+	if err != nil {
+		return "", err
+	}
+	ref, err := url.Parse(href)
+	if err != nil {
+		return "", ess.AddCtx("parsing report URL", err)
+	}
+	return base.ResolveReference(ref).String(), nil
+}
@@ -0,0 +1,14 @@
+package uwquest_test
+
+import (
+	"testing"
+)
+
+func TestClient_TaxReceipts(t *testing.T) {
+	receipts, err := client.TaxReceipts()
+	if err != nil {
+		t.Fatalf("Error while fetching tax receipts: %v", err)
+	}
+
+	t.Logf("Got tax receipts: %v", receipts)
+}
@@ -4,6 +4,9 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
 
 	gq "github.com/PuerkitoBio/goquery"
 	ess "github.com/unixpickle/essentials"
@@ -22,6 +25,29 @@ func (t *Term) String() string {
 		t.Index, t.Name, t.Career, t.Institution)
 }
 
+// termMonths maps the seasons in term names to the month that each term
+// starts in.
+var termMonths = map[string]int{"Winter": 1, "Spring": 5, "Fall": 9}
+
+// Code returns the UW term code for t (i.e. "1189" for Fall 2018), which
+// Quest uses to identify terms in search forms.
+func (t *Term) Code() (string, error) {
+	parts := strings.Fields(t.Name)
+	if len(parts) != 2 {
+		return "", fmt.Errorf("uwquest: unexpected term name '%s'", t.Name)
+	}
+	month, ok := termMonths[parts[0]]
+	if !ok {
+		return "", fmt.Errorf("uwquest: unknown season in term name '%s'",
+			t.Name)
+	}
+	year, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return "", ess.AddCtx("uwquest: parsing year in term name", err)
+	}
+	return strconv.Itoa((year-1900)*10 + month), nil
+}
+
 // Terms fetches all the terms that a student has been enrolled for.
 func (c *Client) Terms() ([]*Term, error) {
 	res, err := c.Session.Get(GradesURL)
@@ -85,6 +111,34 @@ func (c *Client) TermsWithSchedule() ([]*Term, error) {
 	return terms, ess.AddCtx("uwquest: closing response body", err)
 }
 
+// termCode returns the code of the term in terms with index termIndex.
+func termCode(terms []*Term, termIndex int) (string, error) {
+	for _, term := range terms {
+		if term.Index == termIndex {
+			return term.Code()
+		}
+	}
+	return "", fmt.Errorf("uwquest: no term with index %d", termIndex)
+}
+
+// selectTerm fetches the Quest page at pageURL, and selects the term with index
+// termIndex if the page asks for one.
+func (c *Client) selectTerm(pageURL string, termIndex int) (*gq.Document,
+	error) {
+	page, err := c.fetchPage(pageURL)
+	if err != nil {
+		return nil, ess.AddCtx("fetching page", err)
+	}
+	if page.Find(`#SSR_DUMMY_RECV1\$scroll\$0`).Length() == 0 {
+		return page, nil // page only has a single term
+	}
+
+	form := make(url.Values)
+	form.Set("SSR_DUMMY_RECV1$sels$0$$0", strconv.Itoa(termIndex))
+	page, err = c.submitPage(pageURL, page, "DERIVED_SSS_SCT_SSR_PB_GO", form)
+	return page, ess.AddCtx("selecting term", err)
+}
+
 func parseTerms(tableBody *gq.Selection) ([]*Term, error) {
 	var (
 		terms []*Term
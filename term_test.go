@@ -2,6 +2,8 @@ package uwquest_test
 
 import (
 	"testing"
+
+	"github.com/stevenxie/uwquest"
 )
 
 func TestClient_Terms(t *testing.T) {
@@ -29,3 +31,14 @@ func TestClient_TermsWithSchedule(t *testing.T) {
 
 	t.Logf("Got terms with schedule: %v", terms)
 }
+
+func TestTerm_Code(t *testing.T) {
+	term := &uwquest.Term{Name: "Fall 2018"}
+	code, err := term.Code()
+	if err != nil {
+		t.Fatalf("Error while determining term code: %v", err)
+	}
+	if code != "1189" {
+		t.Errorf("Expected term code 1189, got %s", code)
+	}
+}
@@ -0,0 +1,53 @@
+package uwquest
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	// Embed the time zone database, so that Location loads even on systems
+	// without one.
+	_ "time/tzdata"
+)
+
+// Location is the time zone that Quest reports dates and times in.
+var Location = loadLocation()
+
+func loadLocation() *time.Location {
+	loc, err := time.LoadLocation("America/Toronto")
+	if err != nil {
+		// Unreachable, since the time zone database is embedded.
+		panic(fmt.Sprintf("uwquest: loading time zone: %v", err))
+	}
+	return loc
+}
+
+// questDateLayouts are the layouts that Quest formats dates with.
+var questDateLayouts = []string{"01/02/2006", "2006/01/02", "Jan 2, 2006",
+	"January 2, 2006"}
+
+// parseQuestDate parses a date shown on Quest, in Location.
+func parseQuestDate(text string) (time.Time, error) {
+	text = strings.TrimSpace(text)
+	for _, layout := range questDateLayouts {
+		if t, err := time.ParseInLocation(layout, text, Location); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("unexpected date format '%s'", text)
+}
+
+// parseQuestTime parses a date and a time of day shown on Quest (i.e.
+// "07/16/2018" and "8:00AM"), in Location.
+func parseQuestTime(date, clock string) (time.Time, error) {
+	t, err := parseQuestDate(date)
+	if err != nil {
+		return time.Time{}, err
+	}
+	c, err := time.Parse("3:04PM", strings.ToUpper(strings.TrimSpace(clock)))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("unexpected time format '%s'", clock)
+	}
+	return time.Date(t.Year(), t.Month(), t.Day(), c.Hour(), c.Minute(), 0, 0,
+		Location), nil
+}
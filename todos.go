@@ -0,0 +1,77 @@
+package uwquest
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	gq "github.com/PuerkitoBio/goquery"
+	ess "github.com/unixpickle/essentials"
+)
+
+// A ToDo is an item on the Student Center's To Do list, such as a document
+// that the student is required to submit.
+type ToDo struct {
+	Index       int
+	Title       string
+	DueDate     time.Time // zero if the item has no due date
+	Status      string
+	Department  string // the department responsible for the item
+	Description string
+}
+
+func (td *ToDo) String() string {
+	return fmt.Sprintf("ToDo{Index: %d, Title: %s, DueDate: %s, Status: %s, "+
+		"Department: %s, Description: %s}", td.Index, td.Title,
+		td.DueDate.Format("2006-01-02"), td.Status, td.Department,
+		td.Description)
+}
+
+// ToDos fetches the items on the Student Center's To Do list.
+//
+// It returns nil if the list is empty.
+func (c *Client) ToDos() ([]*ToDo, error) {
+	list, err := c.followStudentCenterLink("DERIVED_SSS_SCL_SSS_MORE_TODOS")
+	if err != nil {
+		return nil, ess.AddCtx("uwquest: fetching To Do list", err)
+	}
+	if list == nil {
+		return nil, nil // no To Do items
+	}
+
+	var todos []*ToDo
+	err = c.visitDetailPages(StudentCenterURL, list, "DERIVED_SSS_CHL_ITEM_DESCR",
+		"DERIVED_SSS_CHL_SSS_RETURN_PB",
+		func(index int, detail *gq.Document) error {
+			todo, err := parseToDoDetail(detail.Selection)
+			if err != nil {
+				return err
+			}
+			todo.Index = index
+			todos = append(todos, todo)
+			return nil
+		})
+	return todos, ess.AddCtx("uwquest: fetching To Do item details", err)
+}
+
+// parseToDoDetail parses a To Do item's detail page into a ToDo.
+func parseToDoDetail(sel *gq.Selection) (*ToDo, error) {
+	todo := &ToDo{
+		Title:       fieldText(sel, "DERIVED_SSS_CHL_ITEM_DESCR"),
+		Status:      fieldText(sel, "DERIVED_SSS_CHL_ITEM_STATUS_DESCR"),
+		Department:  fieldText(sel, "DERIVED_SSS_CHL_DEPT_DESCR"),
+		Description: fieldText(sel, "SCC_CHKLST_ITEM_DESCRLONG"),
+	}
+	if todo.Title == "" {
+		return nil, errors.New("could not find item title")
+	}
+
+	if text := fieldText(sel, "PERSON_CHK_ITM_DUE_DT"); text != "" {
+		date, err := parseQuestDate(text)
+		if err != nil {
+			return nil, ess.AddCtx("parsing due date", err)
+		}
+		todo.DueDate = date
+	}
+	return todo, nil
+}
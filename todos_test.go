@@ -0,0 +1,14 @@
+package uwquest_test
+
+import (
+	"testing"
+)
+
+func TestClient_ToDos(t *testing.T) {
+	todos, err := client.ToDos()
+	if err != nil {
+		t.Fatalf("Error while fetching To Do items: %v", err)
+	}
+
+	t.Logf("Got To Do items: %v", todos)
+}
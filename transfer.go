@@ -0,0 +1,104 @@
+package uwquest
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	gq "github.com/PuerkitoBio/goquery"
+	ess "github.com/unixpickle/essentials"
+)
+
+// A TransferCredit is credit that a student has received for work done
+// elsewhere, such as at another institution or on an AP or IB exam.
+type TransferCredit struct {
+	Index          int
+	Source         string // the institution or test, i.e. "AP Exams"
+	IncomingCourse string // i.e. "Calculus BC"
+	Score          string // the incoming grade or test score, if any
+	Equivalent     string // the equivalent UW course, i.e. "MATH 137"
+	Units          *float32
+	PostDate       time.Time // zero if Quest shows no post date
+}
+
+func (tc *TransferCredit) String() string {
+	postDate := ""
+	if !tc.PostDate.IsZero() {
+		postDate = tc.PostDate.Format("2006-01-02")
+	}
+	return fmt.Sprintf("TransferCredit{Index: %d, Source: %s, "+
+		"IncomingCourse: %s, Score: %s, Equivalent: %s, Units: %s, "+
+		"PostDate: %s}", tc.Index, tc.Source, tc.IncomingCourse, tc.Score,
+		tc.Equivalent, formatOptionalFloat(tc.Units), postDate)
+}
+
+// CourseGrade converts tc to a CourseGrade with a grade of "CR", so that it
+// counts towards units (but not averages) in TermAverage and Project.
+func (tc *TransferCredit) CourseGrade() *CourseGrade {
+	desc := tc.Source
+	if tc.IncomingCourse != "" {
+		desc += ": " + tc.IncomingCourse
+	}
+	return &CourseGrade{
+		Index:       tc.Index,
+		Name:        tc.Equivalent,
+		Description: desc,
+		Units:       tc.Units,
+		Grade:       "CR",
+	}
+}
+
+// TransferCredits fetches the student's transfer and test credits.
+func (c *Client) TransferCredits() ([]*TransferCredit, error) {
+	page, err := c.fetchPage(TransferCreditURL)
+	if err != nil {
+		return nil, ess.AddCtx("uwquest: fetching transfer credit report", err)
+	}
+	if page, err = c.viewAll(TransferCreditURL, page); err != nil {
+		return nil, ess.AddCtx("uwquest: expanding transfer credit report", err)
+	}
+
+	credits, err := parseTransferCredits(page.Selection)
+	return credits, ess.AddCtx("uwquest: parsing transfer credit report", err)
+}
+
+// parseTransferCredits parses the rows of a transfer credit report. Quest may
+// show no rows at all, if the student has no transfer credit.
+func parseTransferCredits(sel *gq.Selection) ([]*TransferCredit, error) {
+	var (
+		credits []*TransferCredit
+		err     error
+	)
+	sel.Find(`[id^="TRNS_EQUIV_CRSE$"]`).EachWithBreak(
+		func(i int, equiv *gq.Selection) bool {
+			var index int
+			if index, err = selectionIndex(equiv); err != nil {
+				return false
+			}
+			id := func(field string) string {
+				return fmt.Sprintf("%s$%d", field, index)
+			}
+
+			tc := &TransferCredit{
+				Index:          index,
+				Source:         fieldText(sel, id("TRNS_SRC_DESCR")),
+				IncomingCourse: fieldText(sel, id("TRNS_INCOMING_CRSE")),
+				Score:          fieldText(sel, id("TRNS_INCOMING_SCORE")),
+				Equivalent:     strings.TrimSpace(equiv.Text()),
+			}
+			if tc.Units, err = parseOptionalFloat(fieldText(sel,
+				id("TRNS_UNITS"))); err != nil {
+				ess.AddCtxTo(fmt.Sprintf("row %d: parsing units", i), &err)
+				return false
+			}
+			if date := fieldText(sel, id("TRNS_POST_DT")); date != "" {
+				if tc.PostDate, err = parseQuestDate(date); err != nil {
+					ess.AddCtxTo(fmt.Sprintf("row %d: parsing post date", i), &err)
+					return false
+				}
+			}
+			credits = append(credits, tc)
+			return true
+		})
+	return credits, err
+}
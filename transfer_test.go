@@ -0,0 +1,14 @@
+package uwquest_test
+
+import (
+	"testing"
+)
+
+func TestClient_TransferCredits(t *testing.T) {
+	credits, err := client.TransferCredits()
+	if err != nil {
+		t.Fatalf("Error while fetching transfer credits: %v", err)
+	}
+
+	t.Logf("Got transfer credits: %v", credits)
+}
@@ -0,0 +1,94 @@
+package watch
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/stevenxie/uwquest"
+	ess "github.com/unixpickle/essentials"
+)
+
+// A FileStore is a GradeStore that keeps grades in a JSON file. It is safe
+// for concurrent use.
+type FileStore struct {
+	Path string
+	mu   sync.Mutex
+}
+
+var _ GradeStore = (*FileStore)(nil)
+
+// NewFileStore returns a FileStore that keeps grades in the file at path,
+// which is created once grades are saved.
+func NewFileStore(path string) *FileStore {
+	return &FileStore{Path: path}
+}
+
+// LatestGrades implements GradeStore.
+func (fs *FileStore) LatestGrades(term string) ([]*uwquest.CourseGrade,
+	error) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	terms, err := fs.load()
+	if err != nil {
+		return nil, err
+	}
+	return terms[term], nil
+}
+
+// SaveGrades implements GradeStore.
+func (fs *FileStore) SaveGrades(term string,
+	grades []*uwquest.CourseGrade) error {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+
+	terms, err := fs.load()
+	if err != nil {
+		return err
+	}
+	if grades == nil {
+		grades = []*uwquest.CourseGrade{} // distinguish from unsaved terms
+	}
+	terms[term] = grades
+
+	data, err := json.MarshalIndent(terms, "", "  ")
+	if err != nil {
+		return ess.AddCtx("watch: encoding grades", err)
+	}
+
+	// Write to a temporary file first, so that the store is never left
+	// partially written.
+	tmp, err := ioutil.TempFile(filepath.Dir(fs.Path), ".grades")
+	if err != nil {
+		return ess.AddCtx("watch: creating temporary file", err)
+	}
+	defer os.Remove(tmp.Name())
+	if _, err = tmp.Write(data); err != nil {
+		tmp.Close()
+		return ess.AddCtx("watch: writing grades", err)
+	}
+	if err = tmp.Close(); err != nil {
+		return ess.AddCtx("watch: writing grades", err)
+	}
+	return ess.AddCtx("watch: replacing grades file",
+		os.Rename(tmp.Name(), fs.Path))
+}
+
+// load reads the grades for every term from the file.
+func (fs *FileStore) load() (map[string][]*uwquest.CourseGrade, error) {
+	terms := make(map[string][]*uwquest.CourseGrade)
+	data, err := ioutil.ReadFile(fs.Path)
+	if os.IsNotExist(err) {
+		return terms, nil
+	}
+	if err != nil {
+		return nil, ess.AddCtx("watch: reading grades file", err)
+	}
+	if err = json.Unmarshal(data, &terms); err != nil {
+		return nil, ess.AddCtx("watch: decoding grades file", err)
+	}
+	return terms, nil
+}
@@ -0,0 +1,199 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/stevenxie/uwquest"
+	"github.com/stevenxie/uwquest/diff"
+	"github.com/stevenxie/uwquest/notify"
+	ess "github.com/unixpickle/essentials"
+)
+
+// A GradeFetcher fetches terms and their grades. It is implemented by
+// *uwquest.Client.
+type GradeFetcher interface {
+	Terms() ([]*uwquest.Term, error)
+	Grades(termIndex int) (*uwquest.TermGrades, error)
+}
+
+var _ GradeFetcher = (*uwquest.Client)(nil)
+
+// A GradeStore persists the latest grades seen for each term, so that a
+// GradeWatcher only reports each grade once, even across restarts.
+type GradeStore interface {
+	// LatestGrades returns the grades last saved for a term, or nil if none
+	// have been saved.
+	LatestGrades(term string) ([]*uwquest.CourseGrade, error)
+	SaveGrades(term string, grades []*uwquest.CourseGrade) error
+}
+
+// QuietHours is a daily period (in uwquest.Location) during which a watcher
+// does not poll Quest. Start and End are hours of the day, from 0 to 23; the
+// period may wrap around midnight. It is empty if Start equals End.
+type QuietHours struct {
+	Start, End int
+}
+
+// Contains reports whether t is within the quiet hours.
+func (qh *QuietHours) Contains(t time.Time) bool {
+	hour := t.In(uwquest.Location).Hour()
+	if qh.Start <= qh.End {
+		return qh.Start <= hour && hour < qh.End
+	}
+	return hour >= qh.Start || hour < qh.End
+}
+
+// Remaining returns the time from t until the quiet hours end, or zero if t is
+// not within the quiet hours.
+func (qh *QuietHours) Remaining(t time.Time) time.Duration {
+	if !qh.Contains(t) {
+		return 0
+	}
+	t = t.In(uwquest.Location)
+	end := time.Date(t.Year(), t.Month(), t.Day(), qh.End, 0, 0, 0,
+		uwquest.Location)
+	if !end.After(t) {
+		end = end.AddDate(0, 0, 1)
+	}
+	return end.Sub(t)
+}
+
+// A GradeWatcher polls Quest for the grades of the most recent terms, and
+// notifies a Notifier of each grade that is released.
+type GradeWatcher struct {
+	Fetcher  GradeFetcher
+	Store    GradeStore
+	Notifier notify.Notifier
+
+	// Terms is the number of most recent terms to watch, which defaults to 1.
+	Terms int
+
+	// Interval is the time between polls, which is at least MinInterval.
+	// Jitter is the maximum random delay added to each interval (which
+	// defaults to a tenth of Interval, and is disabled if negative), and
+	// MaxBackoff is the longest delay after repeated errors.
+	Interval   time.Duration
+	Jitter     time.Duration
+	MaxBackoff time.Duration
+
+	// Quiet, if set, are the hours during which the watcher does not poll.
+	Quiet *QuietHours
+
+	// OnError, if set, is called with errors from polling Quest or from
+	// sending notifications. Run keeps going after such errors.
+	OnError func(err error)
+
+	sched *schedule
+}
+
+// Poll checks the grades of the watched terms once, and returns the changes
+// from the grades in w.Store (see diff.Grades). The new grades are saved to
+// w.Store before Poll returns, so each change is only returned once. (Run
+// instead saves the grades of each term once the changes in them have been
+// delivered.)
+//
+// When a term is polled for the first time (i.e. w.Store has no grades for
+// it), its grades are saved without being returned.
+func (w *GradeWatcher) Poll(ctx context.Context) ([]diff.Change, error) {
+	batches, err := w.check(ctx)
+	changes, saveErr := commit(batches)
+	if err == nil {
+		err = saveErr
+	}
+	return changes, err
+}
+
+// check checks the grades of the watched terms once, and returns a batch of
+// changes for each term whose grades changed, without saving them.
+func (w *GradeWatcher) check(ctx context.Context) ([]batch, error) {
+	terms, err := w.Fetcher.Terms()
+	if err != nil {
+		return nil, ess.AddCtx("watch: fetching terms", err)
+	}
+	terms, err = recentTerms(terms, w.Terms)
+	if err != nil {
+		return nil, ess.AddCtx("watch: sorting terms", err)
+	}
+
+	var batches []batch
+	for _, term := range terms {
+		if err := ctx.Err(); err != nil {
+			return batches, err
+		}
+
+		grades, err := w.Fetcher.Grades(term.Index)
+		if err != nil {
+			return batches, ess.AddCtx(fmt.Sprintf(
+				"watch: fetching grades for %s", term.Name), err)
+		}
+		latest, err := w.Store.LatestGrades(term.Name)
+		if err != nil {
+			return batches, ess.AddCtx(fmt.Sprintf(
+				"watch: loading grades for %s", term.Name), err)
+		}
+
+		changes := diff.Grades(term.Name, latest, grades.Courses)
+		if latest != nil && len(changes) == 0 {
+			continue
+		}
+		if latest == nil {
+			changes = nil
+		}
+		batches = append(batches, batch{
+			Changes: changes,
+			Save: func() error {
+				return ess.AddCtx(fmt.Sprintf("watch: saving grades for %s",
+					term.Name), w.Store.SaveGrades(term.Name, grades.Courses))
+			},
+		})
+	}
+	return batches, nil
+}
+
+// recentTerms returns the n most recent terms, from newest to oldest.
+func recentTerms(terms []*uwquest.Term, n int) ([]*uwquest.Term, error) {
+	if n <= 0 {
+		n = 1
+	}
+	codes := make(map[*uwquest.Term]string, len(terms))
+	for _, term := range terms {
+		code, err := term.Code()
+		if err != nil {
+			return nil, err
+		}
+		codes[term] = code
+	}
+
+	sorted := make([]*uwquest.Term, len(terms))
+	copy(sorted, terms)
+	sort.Slice(sorted, func(i, j int) bool {
+		return codes[sorted[i]] > codes[sorted[j]]
+	})
+	if len(sorted) > n {
+		sorted = sorted[:n]
+	}
+	return sorted, nil
+}
+
+// Run polls Quest until ctx is done, notifying w.Notifier of each new grade.
+// It backs off when polls fail, skips polling during w.Quiet, and returns
+// ctx's error once it is done.
+func (w *GradeWatcher) Run(ctx context.Context) error {
+	if w.sched == nil {
+		w.sched = &schedule{
+			Interval:   w.Interval,
+			Jitter:     w.Jitter,
+			MaxBackoff: w.MaxBackoff,
+		}
+	}
+	return run(ctx, w.sched, w.Quiet, w.check, w.Notifier, w.report)
+}
+
+func (w *GradeWatcher) report(err error) {
+	if w.OnError != nil {
+		w.OnError(err)
+	}
+}
@@ -0,0 +1,162 @@
+package watch_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stevenxie/uwquest"
+	"github.com/stevenxie/uwquest/diff"
+	"github.com/stevenxie/uwquest/notify"
+	"github.com/stevenxie/uwquest/watch"
+)
+
+// fakeFetcher serves grades for a fixed set of terms.
+type fakeFetcher struct {
+	TermList     []*uwquest.Term
+	CourseGrades map[int][]*uwquest.CourseGrade
+}
+
+func (ff *fakeFetcher) Terms() ([]*uwquest.Term, error) {
+	return ff.TermList, nil
+}
+
+func (ff *fakeFetcher) Grades(termIndex int) (*uwquest.TermGrades, error) {
+	return &uwquest.TermGrades{Courses: ff.CourseGrades[termIndex]}, nil
+}
+
+func TestGradeWatcher_Poll(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gradewatch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	fetcher := &fakeFetcher{
+		TermList: []*uwquest.Term{
+			{Index: 0, Name: "Fall 2018"},
+			{Index: 1, Name: "Winter 2018"},
+		},
+		CourseGrades: map[int][]*uwquest.CourseGrade{
+			0: {{Name: "CS 135"}, {Name: "MATH 135"}},
+			1: {{Name: "CS 136", Grade: "80"}},
+		},
+	}
+	w := &watch.GradeWatcher{
+		Fetcher: fetcher,
+		Store:   watch.NewFileStore(filepath.Join(dir, "grades.json")),
+	}
+	ctx := context.Background()
+
+	// The first poll only records the current grades.
+	if events, err := w.Poll(ctx); err != nil || len(events) != 0 {
+		t.Fatalf("Expected no events from first poll, got: %v, %v", events, err)
+	}
+
+	fetcher.CourseGrades[0][0].Grade = "85"
+	fetcher.CourseGrades[1][0].Grade = "90" // not watched
+	events, err := w.Poll(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	expected := &diff.GradePosted{
+		Key:   diff.Key{Term: "Fall 2018", Course: "CS 135"},
+		Grade: "85",
+	}
+	if len(events) != 1 || !reflect.DeepEqual(events[0], expected) {
+		t.Errorf("Expected a new grade for CS 135, got: %v", events)
+	}
+
+	// Grades are only reported once, even by a new watcher using the same
+	// store.
+	w = &watch.GradeWatcher{Fetcher: fetcher, Store: w.Store}
+	if events, err = w.Poll(ctx); err != nil || len(events) != 0 {
+		t.Errorf("Expected no repeated events, got: %v, %v", events, err)
+	}
+}
+
+func TestQuietHours(t *testing.T) {
+	at := func(hour int) time.Time {
+		return time.Date(2018, 12, 1, hour, 30, 0, 0, uwquest.Location)
+	}
+	qh := &watch.QuietHours{Start: 23, End: 7}
+
+	quiet := map[int]bool{22: false, 23: true, 2: true, 7: false}
+	for hour, expected := range quiet {
+		if qh.Contains(at(hour)) != expected {
+			t.Errorf("Expected Contains(%d:30) to be %t", hour, expected)
+		}
+	}
+	if d := qh.Remaining(at(23)); d != 7*time.Hour+30*time.Minute {
+		t.Errorf("Expected 7h30m remaining at 23:30, got %s", d)
+	}
+	if d := qh.Remaining(at(12)); d != 0 {
+		t.Errorf("Expected no time remaining at 12:30, got %s", d)
+	}
+}
+
+func TestGradeWatcher_Run_Redelivers(t *testing.T) {
+	dir, err := ioutil.TempDir("", "gradewatch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	fetcher := &fakeFetcher{
+		TermList:     []*uwquest.Term{{Index: 0, Name: "Fall 2018"}},
+		CourseGrades: map[int][]*uwquest.CourseGrade{0: {{Name: "CS 135"}}},
+	}
+	w := &watch.GradeWatcher{
+		Fetcher: fetcher,
+		Store:   watch.NewFileStore(filepath.Join(dir, "grades.json")),
+	}
+	if _, err := w.Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	fetcher.CourseGrades[0][0].Grade = "85"
+
+	// runOnce runs w until its first notification, which is handled by fn.
+	runOnce := func(fn func(e notify.Event) error) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		w.Notifier = notify.NotifierFunc(
+			func(_ context.Context, e notify.Event) error {
+				cancel()
+				return fn(e)
+			})
+		if err := w.Run(ctx); err != context.Canceled {
+			t.Fatalf("Unexpected error from Run: %v", err)
+		}
+	}
+
+	// A grade that could not be delivered is not saved...
+	runOnce(func(notify.Event) error { return errors.New("unavailable") })
+	latest, err := w.Store.LatestGrades("Fall 2018")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(latest) != 1 || latest[0].Grade != "" {
+		t.Errorf("Expected undelivered grade not to be saved, got: %v", latest)
+	}
+
+	// ...so it is delivered by the next poll.
+	var delivered []notify.Event
+	runOnce(func(e notify.Event) error {
+		delivered = append(delivered, e)
+		return nil
+	})
+	if len(delivered) != 1 || delivered[0].EventType() != "grade_posted" {
+		t.Errorf("Expected the grade to be delivered again, got: %v", delivered)
+	}
+	if latest, err = w.Store.LatestGrades("Fall 2018"); err != nil {
+		t.Fatal(err)
+	}
+	if len(latest) != 1 || latest[0].Grade != "85" {
+		t.Errorf("Expected delivered grade to be saved, got: %v", latest)
+	}
+}
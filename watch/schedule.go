@@ -0,0 +1,143 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/stevenxie/uwquest"
+	"github.com/stevenxie/uwquest/diff"
+	"github.com/stevenxie/uwquest/notify"
+	ess "github.com/unixpickle/essentials"
+)
+
+// A ScheduleFetcher fetches terms and their course schedules. It is
+// implemented by *uwquest.Client.
+type ScheduleFetcher interface {
+	TermsWithSchedule() ([]*uwquest.Term, error)
+	Schedules(termIndex int) ([]*uwquest.CourseSchedule, error)
+}
+
+var _ ScheduleFetcher = (*uwquest.Client)(nil)
+
+// A ScheduleStore persists the latest course schedules seen for each term. It
+// is implemented by *store.Store.
+type ScheduleStore interface {
+	// LatestSchedules returns the schedules last saved for a term, and when
+	// they were fetched, which is the zero time if none have been saved.
+	LatestSchedules(term string) ([]*uwquest.CourseSchedule, time.Time, error)
+	SaveSchedules(term string, schedules []*uwquest.CourseSchedule) error
+}
+
+// A ScheduleWatcher polls Quest for the course schedules of the most recent
+// terms, and notifies a Notifier of each change to them (such as a class
+// being dropped, or moving rooms).
+type ScheduleWatcher struct {
+	Fetcher  ScheduleFetcher
+	Store    ScheduleStore
+	Notifier notify.Notifier
+
+	// Terms is the number of most recent terms to watch, which defaults to 1.
+	Terms int
+
+	// Interval is the time between polls, which is at least MinInterval.
+	// Jitter is the maximum random delay added to each interval (which
+	// defaults to a tenth of Interval, and is disabled if negative), and
+	// MaxBackoff is the longest delay after repeated errors.
+	Interval   time.Duration
+	Jitter     time.Duration
+	MaxBackoff time.Duration
+
+	// Quiet, if set, are the hours during which the watcher does not poll.
+	Quiet *QuietHours
+
+	// OnError, if set, is called with errors from polling Quest or from
+	// sending notifications. Run keeps going after such errors.
+	OnError func(err error)
+
+	sched *schedule
+}
+
+// Poll checks the schedules of the watched terms once, and returns the
+// changes from the schedules in w.Store (see diff.Schedules). The new
+// schedules are saved to w.Store before Poll returns, so each change is only
+// returned once. (Run instead saves the schedules of each term once the
+// changes in them have been delivered.)
+//
+// When a term is polled for the first time (i.e. w.Store has no schedules for
+// it), its schedules are saved without being returned.
+func (w *ScheduleWatcher) Poll(ctx context.Context) ([]diff.Change, error) {
+	batches, err := w.check(ctx)
+	changes, saveErr := commit(batches)
+	if err == nil {
+		err = saveErr
+	}
+	return changes, err
+}
+
+// check checks the schedules of the watched terms once, and returns a batch
+// of changes for each term whose schedules changed, without saving them.
+func (w *ScheduleWatcher) check(ctx context.Context) ([]batch, error) {
+	terms, err := w.Fetcher.TermsWithSchedule()
+	if err != nil {
+		return nil, ess.AddCtx("watch: fetching terms", err)
+	}
+	terms, err = recentTerms(terms, w.Terms)
+	if err != nil {
+		return nil, ess.AddCtx("watch: sorting terms", err)
+	}
+
+	var batches []batch
+	for _, term := range terms {
+		if err := ctx.Err(); err != nil {
+			return batches, err
+		}
+
+		schedules, err := w.Fetcher.Schedules(term.Index)
+		if err != nil {
+			return batches, ess.AddCtx(fmt.Sprintf(
+				"watch: fetching schedules for %s", term.Name), err)
+		}
+		latest, fetched, err := w.Store.LatestSchedules(term.Name)
+		if err != nil {
+			return batches, ess.AddCtx(fmt.Sprintf(
+				"watch: loading schedules for %s", term.Name), err)
+		}
+
+		changes := diff.Schedules(term.Name, latest, schedules)
+		if !fetched.IsZero() && len(changes) == 0 {
+			continue
+		}
+		if fetched.IsZero() {
+			changes = nil
+		}
+		batches = append(batches, batch{
+			Changes: changes,
+			Save: func() error {
+				return ess.AddCtx(fmt.Sprintf("watch: saving schedules for %s",
+					term.Name), w.Store.SaveSchedules(term.Name, schedules))
+			},
+		})
+	}
+	return batches, nil
+}
+
+// Run polls Quest until ctx is done, notifying w.Notifier of each change. It
+// backs off when polls fail, skips polling during w.Quiet, and returns ctx's
+// error once it is done.
+func (w *ScheduleWatcher) Run(ctx context.Context) error {
+	if w.sched == nil {
+		w.sched = &schedule{
+			Interval:   w.Interval,
+			Jitter:     w.Jitter,
+			MaxBackoff: w.MaxBackoff,
+		}
+	}
+	return run(ctx, w.sched, w.Quiet, w.check, w.Notifier, w.report)
+}
+
+func (w *ScheduleWatcher) report(err error) {
+	if w.OnError != nil {
+		w.OnError(err)
+	}
+}
@@ -0,0 +1,77 @@
+package watch_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stevenxie/uwquest"
+	"github.com/stevenxie/uwquest/watch"
+)
+
+// fakeScheduleFetcher serves the schedules in Current for a single term.
+type fakeScheduleFetcher struct {
+	Current []*uwquest.CourseSchedule
+}
+
+func (f *fakeScheduleFetcher) TermsWithSchedule() ([]*uwquest.Term, error) {
+	return []*uwquest.Term{{Index: 0, Name: "Fall 2018"}}, nil
+}
+
+func (f *fakeScheduleFetcher) Schedules(int) ([]*uwquest.CourseSchedule,
+	error) {
+	return f.Current, nil
+}
+
+// memoryScheduleStore keeps schedules in memory.
+type memoryScheduleStore map[string][]*uwquest.CourseSchedule
+
+func (m memoryScheduleStore) LatestSchedules(term string) (
+	[]*uwquest.CourseSchedule, time.Time, error) {
+	schedules, ok := m[term]
+	if !ok {
+		return nil, time.Time{}, nil
+	}
+	return schedules, time.Now(), nil
+}
+
+func (m memoryScheduleStore) SaveSchedules(term string,
+	schedules []*uwquest.CourseSchedule) error {
+	m[term] = schedules
+	return nil
+}
+
+func TestScheduleWatcher_Poll(t *testing.T) {
+	fetcher := &fakeScheduleFetcher{Current: []*uwquest.CourseSchedule{{
+		Name:    "CS 135",
+		Status:  uwquest.Enrolled,
+		Classes: []*uwquest.Class{{Number: 5123, Location: "MC 2065"}},
+	}}}
+	w := &watch.ScheduleWatcher{
+		Fetcher: fetcher,
+		Store:   make(memoryScheduleStore),
+	}
+	ctx := context.Background()
+
+	// The first poll only records the current schedules.
+	if events, err := w.Poll(ctx); err != nil || len(events) != 0 {
+		t.Fatalf("Expected no events from first poll, got: %v, %v", events, err)
+	}
+
+	fetcher.Current = []*uwquest.CourseSchedule{{
+		Name:    "CS 135",
+		Status:  uwquest.Enrolled,
+		Classes: []*uwquest.Class{{Number: 5123, Location: "MC 4020"}},
+	}}
+	events, err := w.Poll(ctx)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(events) != 1 || events[0].EventType() != "room_changed" {
+		t.Errorf("Expected a room change, got: %v", events)
+	}
+
+	if events, err = w.Poll(ctx); err != nil || len(events) != 0 {
+		t.Errorf("Expected no repeated events, got: %v, %v", events, err)
+	}
+}
@@ -0,0 +1,145 @@
+package watch
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/stevenxie/uwquest"
+	"github.com/stevenxie/uwquest/diff"
+	"github.com/stevenxie/uwquest/notify"
+	ess "github.com/unixpickle/essentials"
+)
+
+// A ClassSearcher searches for class sections. It is implemented by
+// *uwquest.Client.
+type ClassSearcher interface {
+	SearchClassesByCode(code string, query *uwquest.ClassQuery) (
+		[]*uwquest.ClassSection, error)
+}
+
+var _ ClassSearcher = (*uwquest.Client)(nil)
+
+// DefaultSearchDelay is the time that a SeatWatcher waits between searching
+// for each of its classes, unless it is configured otherwise.
+const DefaultSearchDelay = 2 * time.Second
+
+// A SeatWatcher polls Quest for changes in the enrollment of a set of classes,
+// and notifies a Notifier when a full class opens up, or when a class's wait
+// list moves.
+type SeatWatcher struct {
+	Searcher ClassSearcher
+	Notifier notify.Notifier
+	Term     *uwquest.Term
+	Classes  []int // the class numbers to watch
+
+	// Interval is the time between polls, which is at least MinInterval.
+	// Jitter is the maximum random delay added to each interval (which
+	// defaults to a tenth of Interval, and is disabled if negative), and
+	// MaxBackoff is the longest delay after repeated errors.
+	Interval   time.Duration
+	Jitter     time.Duration
+	MaxBackoff time.Duration
+
+	// SearchDelay is the time between the searches for each class within a
+	// poll, so that they don't hit Quest all at once. It defaults to
+	// DefaultSearchDelay, and is disabled if negative.
+	SearchDelay time.Duration
+
+	// OnError, if set, is called with errors from polling Quest or from
+	// sending notifications. Run keeps going after such errors.
+	OnError func(err error)
+
+	sections map[int]*uwquest.ClassSection
+	sched    *schedule
+}
+
+// Poll checks each watched class once, and returns the events since the
+// previous poll, which are *diff.SeatEvents. The first poll only records each
+// class's enrollment.
+//
+// If checking a class fails, Poll returns the events for the classes that it
+// had already checked, along with the error.
+func (w *SeatWatcher) Poll(ctx context.Context) ([]diff.Change, error) {
+	batches, err := w.check(ctx)
+	changes, _ := commit(batches) // recording sections can't fail
+	return changes, err
+}
+
+// check checks each watched class once, and returns a batch of events for
+// each class, without recording its enrollment.
+func (w *SeatWatcher) check(ctx context.Context) ([]batch, error) {
+	code, err := w.Term.Code()
+	if err != nil {
+		return nil, err
+	}
+	delay := w.SearchDelay
+	if delay == 0 {
+		delay = DefaultSearchDelay
+	}
+
+	var batches []batch
+	for i, number := range w.Classes {
+		if err := ctx.Err(); err != nil {
+			return batches, err
+		}
+		if i > 0 && delay > 0 {
+			if err := sleep(ctx, delay); err != nil {
+				return batches, err
+			}
+		}
+
+		sections, err := w.Searcher.SearchClassesByCode(code,
+			&uwquest.ClassQuery{ClassNumber: number})
+		if err != nil {
+			return batches, ess.AddCtx(fmt.Sprintf(
+				"watch: searching for class %d", number), err)
+		}
+		var section *uwquest.ClassSection
+		for _, s := range sections {
+			if s.Number == number {
+				section = s
+				break
+			}
+		}
+		if section == nil {
+			return batches, fmt.Errorf("watch: could not find class %d", number)
+		}
+
+		var events []diff.Change
+		if prev, ok := w.sections[number]; ok {
+			events = diff.Sections(w.Term.Name, []*uwquest.ClassSection{prev},
+				[]*uwquest.ClassSection{section})
+		}
+		batches = append(batches, batch{
+			Changes: events,
+			Save: func() error {
+				if w.sections == nil {
+					w.sections = make(map[int]*uwquest.ClassSection)
+				}
+				w.sections[number] = section
+				return nil
+			},
+		})
+	}
+	return batches, nil
+}
+
+// Run polls Quest until ctx is done, notifying w.Notifier of each event. It
+// backs off when polls fail, and returns ctx's error once it is done.
+func (w *SeatWatcher) Run(ctx context.Context) error {
+	if w.sched == nil {
+		w.sched = &schedule{
+			Interval:   w.Interval,
+			Jitter:     w.Jitter,
+			MaxBackoff: w.MaxBackoff,
+		}
+	}
+	return run(ctx, w.sched, nil, w.check, w.Notifier, w.report)
+}
+
+func (w *SeatWatcher) report(err error) {
+	if w.OnError != nil {
+		w.OnError(err)
+	}
+}
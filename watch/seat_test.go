@@ -0,0 +1,145 @@
+package watch_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stevenxie/uwquest"
+	"github.com/stevenxie/uwquest/diff"
+	"github.com/stevenxie/uwquest/notify"
+	"github.com/stevenxie/uwquest/watch"
+)
+
+// fakeSearcher returns the sections in Results, one slice per call.
+type fakeSearcher struct {
+	Results [][]*uwquest.ClassSection
+	Calls   int
+}
+
+func (fs *fakeSearcher) SearchClassesByCode(code string,
+	query *uwquest.ClassQuery) ([]*uwquest.ClassSection, error) {
+	if fs.Calls >= len(fs.Results) {
+		return nil, errors.New("no more results")
+	}
+	results := fs.Results[fs.Calls]
+	fs.Calls++
+
+	var matches []*uwquest.ClassSection
+	for _, s := range results {
+		if s.Number == query.ClassNumber {
+			matches = append(matches, s)
+		}
+	}
+	return matches, nil
+}
+
+func section(status uwquest.ClassStatus, waitlist int) *uwquest.ClassSection {
+	return &uwquest.ClassSection{
+		Course:        "CS 135",
+		Number:        5123,
+		Section:       1,
+		Component:     "LEC",
+		Status:        status,
+		WaitlistTotal: &waitlist,
+	}
+}
+
+func TestSeatWatcher_Poll(t *testing.T) {
+	searcher := &fakeSearcher{Results: [][]*uwquest.ClassSection{
+		{section(uwquest.ClosedStatus, 3)},
+		{section(uwquest.ClosedStatus, 3)},
+		{section(uwquest.ClosedStatus, 2)},
+		{section(uwquest.OpenStatus, 0)},
+	}}
+	w := &watch.SeatWatcher{
+		Searcher: searcher,
+		Term:     &uwquest.Term{Name: "Fall 2018"},
+		Classes:  []int{5123},
+	}
+
+	expected := [][]diff.SeatEventKind{
+		nil, // the first poll only records the class
+		nil,
+		{diff.WaitlistMoved},
+		{diff.SeatOpened},
+	}
+	for i, kinds := range expected {
+		events, err := w.Poll(context.Background())
+		if err != nil {
+			t.Fatalf("Poll %d: unexpected error: %v", i, err)
+		}
+		if len(events) != len(kinds) {
+			t.Fatalf("Poll %d: expected %d events, got: %v", i, len(kinds), events)
+		}
+		for j, e := range events {
+			if se, ok := e.(*diff.SeatEvent); !ok || se.Kind != kinds[j] {
+				t.Errorf("Poll %d: expected %s event, got: %s", i, kinds[j], e)
+			}
+		}
+	}
+
+	if _, err := w.Poll(context.Background()); err == nil {
+		t.Error("Expected an error when the search fails.")
+	}
+}
+
+func TestSeatWatcher_PollSpacesSearches(t *testing.T) {
+	other := section(uwquest.OpenStatus, 0)
+	other.Number = 5124
+	results := []*uwquest.ClassSection{section(uwquest.OpenStatus, 0), other}
+	w := &watch.SeatWatcher{
+		Searcher: &fakeSearcher{
+			Results: [][]*uwquest.ClassSection{results, results},
+		},
+		Term:        &uwquest.Term{Name: "Fall 2018"},
+		Classes:     []int{5123, 5124},
+		SearchDelay: 50 * time.Millisecond,
+	}
+
+	start := time.Now()
+	if _, err := w.Poll(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < w.SearchDelay {
+		t.Errorf("Expected searches to be at least %s apart, but poll took %s",
+			w.SearchDelay, elapsed)
+	}
+}
+
+func TestSeatWatcher_Run(t *testing.T) {
+	var (
+		ctx, cancel = context.WithCancel(context.Background())
+		searcher    = &fakeSearcher{Results: [][]*uwquest.ClassSection{
+			{section(uwquest.ClosedStatus, 0)},
+		}}
+		errs []error
+	)
+	defer cancel()
+
+	w := &watch.SeatWatcher{
+		Searcher: searcher,
+		Notifier: notify.NotifierFunc(func(context.Context, notify.Event) error {
+			return nil
+		}),
+		Term:    &uwquest.Term{Name: "Fall 2018"},
+		Classes: []int{5123},
+		OnError: func(err error) { errs = append(errs, err) },
+	}
+
+	// Since Run waits at least MinInterval between polls, it should only poll
+	// once before being cancelled.
+	done := make(chan error)
+	go func() { done <- w.Run(ctx) }()
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Expected Run to return context.Canceled, got: %v", err)
+	}
+	if searcher.Calls > 1 {
+		t.Errorf("Expected at most 1 search, got %d", searcher.Calls)
+	}
+	if len(errs) != 0 {
+		t.Errorf("Expected no errors, got: %v", errs)
+	}
+}
@@ -0,0 +1,241 @@
+// Package watch polls Quest for changes (such as class seats opening up), and
+// reports them to a notify.Notifier as diff.Changes.
+package watch
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"github.com/stevenxie/uwquest/diff"
+	"github.com/stevenxie/uwquest/notify"
+	ess "github.com/unixpickle/essentials"
+)
+
+// MinInterval is the shortest interval at which a watcher will poll Quest,
+// so that watchers never hammer it with requests. Shorter intervals are
+// raised to MinInterval.
+const MinInterval = time.Minute
+
+// DefaultMaxBackoff is the longest that a watcher will wait between polls
+// when Quest keeps returning errors, unless it is configured otherwise.
+const DefaultMaxBackoff = 30 * time.Minute
+
+// schedule determines how long a watcher waits between polls.
+type schedule struct {
+	Interval   time.Duration
+	Jitter     time.Duration // defaults to a tenth of Interval; <0 disables
+	MaxBackoff time.Duration // defaults to DefaultMaxBackoff
+
+	failures int
+	rand     *rand.Rand
+}
+
+// next returns the delay before the next poll, given the error that the last
+// poll failed with (if any). The delay doubles with each consecutive failure,
+// up to MaxBackoff.
+func (s *schedule) next(err error) time.Duration {
+	interval := s.Interval
+	if interval < MinInterval {
+		interval = MinInterval
+	}
+	maxBackoff := s.MaxBackoff
+	if maxBackoff == 0 {
+		maxBackoff = DefaultMaxBackoff
+	}
+	if maxBackoff < interval {
+		maxBackoff = interval
+	}
+
+	if err != nil {
+		s.failures++
+	} else {
+		s.failures = 0
+	}
+	delay := interval
+	for i := 0; i < s.failures && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+
+	jitter := s.Jitter
+	if jitter == 0 {
+		jitter = interval / 10
+	}
+	if jitter > 0 {
+		if s.rand == nil {
+			s.rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+		}
+		delay += time.Duration(s.rand.Int63n(int64(jitter)))
+	}
+	return delay
+}
+
+// sleep waits for d to elapse, or for ctx to be done.
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// A batch is a set of changes found by polling Quest, along with a function
+// that saves the state that they were found in, so that they aren't found
+// again.
+type batch struct {
+	Changes []diff.Change
+	Save    func() error
+}
+
+// commit saves the state of each batch, and returns the changes from them.
+func commit(batches []batch) ([]diff.Change, error) {
+	var changes []diff.Change
+	for _, b := range batches {
+		if err := b.Save(); err != nil {
+			return changes, err
+		}
+		changes = append(changes, b.Changes...)
+	}
+	return changes, nil
+}
+
+// run calls poll until ctx is done, notifying n of the changes that it
+// returns, and passing errors to report. It waits between polls according to
+// sched, skips polling during quiet (if it is not nil), and returns ctx's
+// error once it is done.
+//
+// Changes are delivered by an outbox, so that each of the notifiers in a
+// notify.Multi is sent each change once, even if the others fail.
+func run(ctx context.Context, sched *schedule, quiet *QuietHours,
+	poll func(ctx context.Context) ([]batch, error), n notify.Notifier,
+	report func(err error)) error {
+	out := newOutbox(n, report)
+	for {
+		if quiet != nil {
+			if d := quiet.Remaining(time.Now()); d > 0 {
+				if err := sleep(ctx, d); err != nil {
+					return err
+				}
+			}
+		}
+
+		batches, pollErr := poll(ctx)
+		if pollErr != nil && ctx.Err() == nil {
+			report(pollErr)
+		}
+		out.deliver(ctx, batches)
+
+		if err := sleep(ctx, sched.next(pollErr)); err != nil {
+			return err
+		}
+	}
+}
+
+// An outbox delivers changes to a set of notifiers, recording which notifiers
+// each change has been sent to.
+//
+// A batch is saved once each of its changes has reached at least one
+// notifier; the notifiers that missed a change are retried on later
+// deliveries, without resending it to the others. Changes that reached no
+// notifier leave their batch unsaved, so that they are found (and delivered)
+// again by the next poll, or after a restart.
+type outbox struct {
+	notifiers []notify.Notifier
+	report    func(err error)
+
+	sent  map[string]map[int]bool // notifiers that were sent each change
+	retry []diff.Change           // saved changes that some notifiers missed
+}
+
+func newOutbox(n notify.Notifier, report func(err error)) *outbox {
+	notifiers := []notify.Notifier{n}
+	if m, ok := n.(notify.Multi); ok {
+		notifiers = m
+	}
+	return &outbox{
+		notifiers: notifiers,
+		report:    report,
+		sent:      make(map[string]map[int]bool),
+	}
+}
+
+// changeID identifies c across polls.
+func changeID(c diff.Change) string {
+	key := c.ChangeKey()
+	return c.EventType() + "\x00" + key.Term + "\x00" + key.Course + "\x00" +
+		c.String()
+}
+
+// send sends c to each notifier that hasn't been sent it yet. It reports
+// whether any notifier has been sent c, and whether all of them have. It
+// stops early once ctx is done.
+func (o *outbox) send(ctx context.Context, c diff.Change) (some, all bool) {
+	id := changeID(c)
+	sent := o.sent[id]
+	for i, n := range o.notifiers {
+		if sent[i] {
+			continue
+		}
+		if err := n.Notify(ctx, c); err != nil {
+			if ctx.Err() != nil {
+				break
+			}
+			o.report(ess.AddCtx("watch: sending notification", err))
+			continue
+		}
+		if sent == nil {
+			sent = make(map[int]bool)
+			o.sent[id] = sent
+		}
+		sent[i] = true
+	}
+	return len(sent) > 0, len(sent) == len(o.notifiers)
+}
+
+// deliver retries the changes that some notifiers missed, then delivers the
+// changes in each batch, saving the batches whose changes have all reached a
+// notifier. It stops once ctx is done.
+func (o *outbox) deliver(ctx context.Context, batches []batch) {
+	retry := o.retry
+	o.retry = nil
+	for _, c := range retry {
+		if _, all := o.send(ctx, c); all {
+			delete(o.sent, changeID(c))
+		} else {
+			o.retry = append(o.retry, c)
+		}
+	}
+
+	for _, b := range batches {
+		reached := true
+		for _, c := range b.Changes {
+			if some, _ := o.send(ctx, c); !some {
+				reached = false
+			}
+		}
+		if !reached {
+			if ctx.Err() != nil {
+				return
+			}
+			continue
+		}
+		if err := b.Save(); err != nil {
+			o.report(err)
+			continue
+		}
+		for _, c := range b.Changes {
+			if len(o.sent[changeID(c)]) == len(o.notifiers) {
+				delete(o.sent, changeID(c))
+			} else {
+				o.retry = append(o.retry, c)
+			}
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package watch
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stevenxie/uwquest/diff"
+	"github.com/stevenxie/uwquest/notify"
+)
+
+func TestSchedule_Next(t *testing.T) {
+	s := &schedule{
+		Interval:   time.Second, // raised to MinInterval
+		Jitter:     -1,          // disables jitter
+		MaxBackoff: 5 * MinInterval,
+	}
+	failed := errors.New("failed")
+
+	for i, c := range []struct {
+		Err      error
+		Expected time.Duration
+	}{
+		{nil, MinInterval},
+		{failed, 2 * MinInterval},
+		{failed, 4 * MinInterval},
+		{failed, 5 * MinInterval},
+		{failed, 5 * MinInterval},
+		{nil, MinInterval},
+	} {
+		if delay := s.next(c.Err); delay != c.Expected {
+			t.Errorf("Call %d: expected delay of %s, got %s", i, c.Expected, delay)
+		}
+	}
+}
+
+func TestSchedule_NextJitter(t *testing.T) {
+	s := &schedule{Interval: 10 * time.Minute}
+	for i := 0; i < 100; i++ {
+		delay := s.next(nil)
+		if delay < 10*time.Minute || delay >= 11*time.Minute {
+			t.Fatalf("Expected delay within a tenth of the interval, got %s", delay)
+		}
+	}
+}
+
+func TestOutbox_Deliver(t *testing.T) {
+	var delivered []notify.Event
+	working := notify.NotifierFunc(func(_ context.Context, e notify.Event) error {
+		delivered = append(delivered, e)
+		return nil
+	})
+	failures := 0
+	broken := notify.NotifierFunc(func(context.Context, notify.Event) error {
+		failures++
+		return errors.New("unavailable")
+	})
+
+	var reports []error
+	out := newOutbox(notify.Multi{working, broken},
+		func(err error) { reports = append(reports, err) })
+
+	saves := 0
+	change := &diff.GradePosted{
+		Key:   diff.Key{Term: "Fall 2018", Course: "CS 135"},
+		Grade: "85",
+	}
+	b := batch{
+		Changes: []diff.Change{change},
+		Save:    func() error { saves++; return nil },
+	}
+
+	// The change reaches the working notifier, so the batch is saved, and
+	// later deliveries only retry the broken notifier.
+	out.deliver(context.Background(), []batch{b})
+	for i := 0; i < 3; i++ {
+		out.deliver(context.Background(), nil)
+	}
+	if len(delivered) != 1 {
+		t.Errorf("Expected the change to be delivered once, got %d times",
+			len(delivered))
+	}
+	if saves != 1 {
+		t.Errorf("Expected the batch to be saved once, got %d saves", saves)
+	}
+	if failures != 4 || len(reports) != 4 {
+		t.Errorf("Expected 4 failed deliveries to be reported, got %d (%d "+
+			"reported)", failures, len(reports))
+	}
+}
+
+func TestOutbox_DeliverUnreached(t *testing.T) {
+	var delivered []notify.Event
+	fail := true
+	n := notify.NotifierFunc(func(_ context.Context, e notify.Event) error {
+		if fail {
+			return errors.New("unavailable")
+		}
+		delivered = append(delivered, e)
+		return nil
+	})
+	out := newOutbox(n, func(error) {})
+
+	saves := 0
+	b := batch{
+		Changes: []diff.Change{&diff.GradePosted{
+			Key:   diff.Key{Term: "Fall 2018", Course: "CS 135"},
+			Grade: "85",
+		}},
+		Save: func() error { saves++; return nil },
+	}
+
+	// A change that reached no notifier leaves its batch unsaved, so it is
+	// delivered when the next poll finds it again.
+	out.deliver(context.Background(), []batch{b})
+	if saves != 0 {
+		t.Errorf("Expected unreached batch not to be saved")
+	}
+	fail = false
+	out.deliver(context.Background(), []batch{b})
+	if saves != 1 || len(delivered) != 1 {
+		t.Errorf("Expected 1 save and 1 delivery, got %d and %d", saves,
+			len(delivered))
+	}
+	if len(out.sent) != 0 || len(out.retry) != 0 {
+		t.Errorf("Expected delivered change to be forgotten")
+	}
+}